@@ -25,12 +25,24 @@ type MongoConfig struct {
 	Password string `yaml:"password"`
 	Name     string `yaml:"name"`
 	Timeout  int    `yaml:"timeout"`
+	// VersionField is an optional name of the field that holds document version,
+	// if set it's used to detect concurrent modifications of edited documents
+	VersionField string `yaml:"versionField,omitempty"`
+	// References maps fields holding ObjectIDs (db.collection.field) to collections
+	// with referenced documents (collection or db.collection)
+	References map[string]string `yaml:"references,omitempty"`
 }
 
 type LogConfig struct {
 	Path        string `yaml:"path"`
 	Level       string `yaml:"level"`
 	PrettyPrint bool   `yaml:"prettyPrint"`
+	// AuditPath is a path to the append-only log of all write operations,
+	// audit log is disabled if it's empty
+	AuditPath string `yaml:"auditPath,omitempty"`
+	// AuditExcludeImages is a list of namespaces (db.collection or db.*)
+	// for which documents are not stored in the audit log
+	AuditExcludeImages []string `yaml:"auditExcludeImages,omitempty"`
 }
 
 type EditorConfig struct {
@@ -38,20 +50,62 @@ type EditorConfig struct {
 	Env     string `yaml:"env"`
 }
 
+type ContentConfig struct {
+	// FlattenNested shows fields of nested objects as separate
+	// dotted columns (address.city) in the table view
+	FlattenNested bool `yaml:"flattenNested"`
+	// FlattenDepth is the maximum number of nested levels shown as columns,
+	// it's a pointer as 0 is a valid value that can't be replaced by default
+	FlattenDepth *int `yaml:"flattenDepth"`
+}
+
+// GetFlattenDepth returns the maximum number of nested levels shown as columns
+func (c ContentConfig) GetFlattenDepth() int {
+	if c.FlattenDepth == nil || *c.FlattenDepth < 0 {
+		return 0
+	}
+	return *c.FlattenDepth
+}
+
+type SchemaConfig struct {
+	// SampleSize is the number of documents sampled for schema analysis
+	SampleSize int `yaml:"sampleSize"`
+}
+
+type DashboardConfig struct {
+	// RefreshInterval is the number of seconds between refreshes of server status
+	RefreshInterval int `yaml:"refreshInterval"`
+}
+
+type OperationsConfig struct {
+	// RefreshInterval is the number of seconds between refreshes of current operations
+	RefreshInterval int `yaml:"refreshInterval"`
+}
+
+type TopologyConfig struct {
+	// RefreshInterval is the number of seconds between refreshes of the topology
+	RefreshInterval int `yaml:"refreshInterval"`
+}
+
 type StylesConfig struct {
 	BetterSymbols bool   `yaml:"betterSymbols"`
 	CurrentStyle  string `yaml:"currentStyle"`
 }
 
 type Config struct {
-	Version            string        `yaml:"version"`
-	Log                LogConfig     `yaml:"log"`
-	Editor             EditorConfig  `yaml:"editor"`
-	ShowConnectionPage bool          `yaml:"showConnectionPage"`
-	ShowWelcomePage    bool          `yaml:"showWelcomePage"`
-	CurrentConnection  string        `yaml:"currentConnection"`
-	Connections        []MongoConfig `yaml:"connections"`
-	Styles             StylesConfig  `yaml:"styles"`
+	Version            string           `yaml:"version"`
+	Log                LogConfig        `yaml:"log"`
+	Editor             EditorConfig     `yaml:"editor"`
+	Content            ContentConfig    `yaml:"content"`
+	Schema             SchemaConfig     `yaml:"schema"`
+	Dashboard          DashboardConfig  `yaml:"dashboard"`
+	Operations         OperationsConfig `yaml:"operations"`
+	Topology           TopologyConfig   `yaml:"topology"`
+	ShowConnectionPage bool             `yaml:"showConnectionPage"`
+	ShowWelcomePage    bool             `yaml:"showWelcomePage"`
+	CurrentConnection  string           `yaml:"currentConnection"`
+	Connections        []MongoConfig    `yaml:"connections"`
+	Styles             StylesConfig     `yaml:"styles"`
 }
 
 // LoadConfig loads the config file
@@ -66,7 +120,30 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
-	return util.LoadConfigFile(defaultConfig, configPath)
+	config, err := util.LoadConfigFile(defaultConfig, configPath)
+	if err != nil {
+		return nil, err
+	}
+	config.setPositiveDefaults(defaultConfig)
+
+	return config, nil
+}
+
+// setPositiveDefaults sets defaults for int values that must be positive,
+// ints are not merged with defaults as 0 may be a valid value for them
+func (c *Config) setPositiveDefaults(defaultConfig *Config) {
+	if c.Schema.SampleSize <= 0 {
+		c.Schema.SampleSize = defaultConfig.Schema.SampleSize
+	}
+	if c.Dashboard.RefreshInterval <= 0 {
+		c.Dashboard.RefreshInterval = defaultConfig.Dashboard.RefreshInterval
+	}
+	if c.Operations.RefreshInterval <= 0 {
+		c.Operations.RefreshInterval = defaultConfig.Operations.RefreshInterval
+	}
+	if c.Topology.RefreshInterval <= 0 {
+		c.Topology.RefreshInterval = defaultConfig.Topology.RefreshInterval
+	}
 }
 
 // loadDefaults loads the default config settings
@@ -81,6 +158,23 @@ func (c *Config) loadDefaults() {
 		Command: "",
 		Env:     "EDITOR",
 	}
+	flattenDepth := 2
+	c.Content = ContentConfig{
+		FlattenNested: false,
+		FlattenDepth:  &flattenDepth,
+	}
+	c.Schema = SchemaConfig{
+		SampleSize: 1000,
+	}
+	c.Dashboard = DashboardConfig{
+		RefreshInterval: 2,
+	}
+	c.Operations = OperationsConfig{
+		RefreshInterval: 2,
+	}
+	c.Topology = TopologyConfig{
+		RefreshInterval: 5,
+	}
 	c.Styles = StylesConfig{
 		BetterSymbols: true,
 		CurrentStyle:  "default.yaml",
@@ -19,17 +19,27 @@ type (
 	// There are views that have only keybindings and some have
 	// nested keybindings of their children views
 	KeyBindings struct {
-		Global     GlobalKeys     `json:"global"`
-		Help       HelpKeys       `json:"help"`
-		Welcome    WelcomeKeys    `json:"welcome"`
-		Connection ConnectionKeys `json:"connection"`
-		Main       MainKeys       `json:"main"`
-		Database   DatabaseKeys   `json:"databases"`
-		Content    ContentKeys    `json:"content"`
-		QueryBar   QueryBar       `json:"queryBar"`
-		SortBar    SortBar        `json:"sortBar"`
-		Peeker     PeekerKeys     `json:"peeker"`
-		History    HistoryKeys    `json:"history"`
+		Global      GlobalKeys      `json:"global"`
+		Help        HelpKeys        `json:"help"`
+		Welcome     WelcomeKeys     `json:"welcome"`
+		Connection  ConnectionKeys  `json:"connection"`
+		Main        MainKeys        `json:"main"`
+		Database    DatabaseKeys    `json:"databases"`
+		Content     ContentKeys     `json:"content"`
+		QueryBar    QueryBar        `json:"queryBar"`
+		SortBar     SortBar         `json:"sortBar"`
+		Peeker      PeekerKeys      `json:"peeker"`
+		History     HistoryKeys     `json:"history"`
+		Journal     JournalKeys     `json:"journal"`
+		Columns     ColumnsKeys     `json:"columns"`
+		QuickFilter QuickFilterKeys `json:"quickFilter"`
+		Search      SearchKeys      `json:"search"`
+		CommandBar  CommandBarKeys  `json:"commandBar"`
+		Dashboard   DashboardKeys   `json:"dashboard"`
+		Operations  OperationsKeys  `json:"operations"`
+		Profiler    ProfilerKeys    `json:"profiler"`
+		Topology    TopologyKeys    `json:"topology"`
+		Users       UsersKeys       `json:"users"`
 	}
 
 	// Key is a lowest level of keybindings
@@ -56,6 +66,9 @@ type (
 		FocusContent   Key `json:"focusContent"`
 		HideDatabase   Key `json:"hideDatabases"`
 		ShowServerInfo Key `json:"showServerInfo"`
+		ShowOperations Key `json:"showOperations"`
+		ShowTopology   Key `json:"showTopology"`
+		ShowCommandBar Key `json:"showCommandBar"`
 	}
 
 	DatabaseKeys struct {
@@ -64,6 +77,19 @@ type (
 		CollapseAll      Key `json:"collapseAll"`
 		AddCollection    Key `json:"addCollection"`
 		DeleteCollection Key `json:"deleteCollection"`
+		ShowSchema       Key `json:"showSchema"`
+		ShowStats        Key `json:"showStats"`
+		ToggleCounts     Key `json:"toggleCounts"`
+		RefreshNode      Key `json:"refreshNode"`
+		CreateCollection Key `json:"createCollection"`
+		RenameCollection Key `json:"renameCollection"`
+		ConvertToCapped  Key `json:"convertToCapped"`
+		CreateView       Key `json:"createView"`
+		CreateDatabase   Key `json:"createDatabase"`
+		DropDatabase     Key `json:"dropDatabase"`
+		ShowProfiler     Key `json:"showProfiler"`
+		ShowUsers        Key `json:"showUsers"`
+		ShowValidation   Key `json:"showValidation"`
 	}
 
 	ContentKeys struct {
@@ -72,6 +98,7 @@ type (
 		ViewDocument      Key `json:"viewDocument"`
 		AddDocument       Key `json:"addDocument"`
 		EditDocument      Key `json:"editDocument"`
+		InlineEdit        Key `json:"inlineEdit"`
 		DuplicateDocument Key `json:"duplicateDocument"`
 		DeleteDocument    Key `json:"deleteDocument"`
 		CopyLine          Key `json:"copyValue"`
@@ -83,6 +110,21 @@ type (
 		NextPage          Key `json:"nextPage"`
 		PreviousPage      Key `json:"previousPage"`
 		ToggleSort        Key `json:"toggleSort"`
+		ToggleFlatten     Key `json:"toggleFlatten"`
+		ExpandArray       Key `json:"expandArray"`
+		ShowColumns       Key `json:"showColumns"`
+		QuickFilter       Key `json:"quickFilter"`
+		FollowReference   Key `json:"followReference"`
+		WidenColumn       Key `json:"widenColumn"`
+		NarrowColumn      Key `json:"narrowColumn"`
+		Undo              Key `json:"undo"`
+		Redo              Key `json:"redo"`
+		ShowJournal       Key `json:"showJournal"`
+		Search            Key `json:"search"`
+		SearchBackward    Key `json:"searchBackward"`
+		NextMatch         Key `json:"nextMatch"`
+		PreviousMatch     Key `json:"previousMatch"`
+		CopyCollection    Key `json:"copyCollection"`
 
 		// MultipleSelect    Key      `json:"multipleSelect"`
 		// ClearSelection   Key      `json:"clearSelection"`
@@ -126,11 +168,21 @@ type (
 	}
 
 	PeekerKeys struct {
-		MoveToTop     Key `json:"moveToTop"`
-		MoveToBottom  Key `json:"moveToBottom"`
-		CopyHighlight Key `json:"popyHighlight"`
-		CopyValue     Key `json:"copyValue"`
-		Refresh       Key `json:"refresh"`
+		MoveToTop       Key `json:"moveToTop"`
+		MoveToBottom    Key `json:"moveToBottom"`
+		CopyHighlight   Key `json:"popyHighlight"`
+		CopyValue       Key `json:"copyValue"`
+		Refresh         Key `json:"refresh"`
+		FollowReference Key `json:"followReference"`
+		Back            Key `json:"back"`
+		ToggleTree      Key `json:"toggleTree"`
+		ExpandNode      Key `json:"expandNode"`
+		CollapseNode    Key `json:"collapseNode"`
+		CopyPath        Key `json:"copyPath"`
+		Search          Key `json:"search"`
+		SearchBackward  Key `json:"searchBackward"`
+		NextMatch       Key `json:"nextMatch"`
+		PreviousMatch   Key `json:"previousMatch"`
 	}
 
 	HistoryKeys struct {
@@ -138,6 +190,68 @@ type (
 		AcceptEntry  Key `json:"acceptEntry"`
 		CloseHistory Key `json:"closeHistory"`
 	}
+
+	ColumnsKeys struct {
+		ToggleHidden Key `json:"toggleHidden"`
+		TogglePinned Key `json:"togglePinned"`
+		MoveUp       Key `json:"moveUp"`
+		MoveDown     Key `json:"moveDown"`
+		CloseColumns Key `json:"closeColumns"`
+	}
+
+	QuickFilterKeys struct {
+		ApplyFilter      Key `json:"applyFilter"`
+		CloseQuickFilter Key `json:"closeQuickFilter"`
+	}
+
+	CommandBarKeys struct {
+		Complete        Key `json:"complete"`
+		PreviousCommand Key `json:"previousCommand"`
+		NextCommand     Key `json:"nextCommand"`
+	}
+
+	SearchKeys struct {
+		AcceptSearch Key `json:"acceptSearch"`
+		ToggleRegex  Key `json:"toggleRegex"`
+		CloseSearch  Key `json:"closeSearch"`
+	}
+
+	JournalKeys struct {
+		UndoEntry    Key `json:"undoEntry"`
+		CloseJournal Key `json:"closeJournal"`
+	}
+
+	DashboardKeys struct {
+		CloseDashboard Key `json:"closeDashboard"`
+	}
+
+	OperationsKeys struct {
+		FilterOperations Key `json:"filterOperations"`
+		KillOperation    Key `json:"killOperation"`
+		CloseOperations  Key `json:"closeOperations"`
+	}
+
+	ProfilerKeys struct {
+		ChangeLevel   Key `json:"changeLevel"`
+		Refresh       Key `json:"refresh"`
+		OpenQuery     Key `json:"openQuery"`
+		CloseProfiler Key `json:"closeProfiler"`
+	}
+
+	TopologyKeys struct {
+		CloseTopology Key `json:"closeTopology"`
+	}
+
+	UsersKeys struct {
+		CreateUser     Key `json:"createUser"`
+		GrantRole      Key `json:"grantRole"`
+		RevokeRole     Key `json:"revokeRole"`
+		ChangePassword Key `json:"changePassword"`
+		DropUser       Key `json:"dropUser"`
+		SwitchFocus    Key `json:"switchFocus"`
+		Refresh        Key `json:"refresh"`
+		CloseUsers     Key `json:"closeUsers"`
+	}
 )
 
 func (k *KeyBindings) loadDefaults() {
@@ -175,7 +289,19 @@ func (k *KeyBindings) loadDefaults() {
 		},
 		ShowServerInfo: Key{
 			Keys:        []string{"Ctrl+K"},
-			Description: "Show server info",
+			Description: "Show server dashboard",
+		},
+		ShowOperations: Key{
+			Keys:        []string{"Ctrl+P"},
+			Description: "Show current operations",
+		},
+		ShowTopology: Key{
+			Keys:        []string{"Ctrl+G"},
+			Description: "Show replica set or sharding topology",
+		},
+		ShowCommandBar: Key{
+			Runes:       []string{":"},
+			Description: "Command line",
 		},
 	}
 
@@ -200,6 +326,58 @@ func (k *KeyBindings) loadDefaults() {
 			Runes:       []string{"D"},
 			Description: "Delete collection",
 		},
+		ShowSchema: Key{
+			Runes:       []string{"S"},
+			Description: "Analyze schema",
+		},
+		ShowStats: Key{
+			Runes:       []string{"I"},
+			Description: "Show statistics",
+		},
+		ToggleCounts: Key{
+			Runes:       []string{"C"},
+			Description: "Toggle document counts",
+		},
+		RefreshNode: Key{
+			Runes:       []string{"R"},
+			Description: "Refresh database",
+		},
+		CreateCollection: Key{
+			Runes:       []string{"O"},
+			Description: "Create collection with options",
+		},
+		RenameCollection: Key{
+			Runes:       []string{"N"},
+			Description: "Rename collection",
+		},
+		ConvertToCapped: Key{
+			Runes:       []string{"P"},
+			Description: "Convert to capped",
+		},
+		CreateView: Key{
+			Runes:       []string{"V"},
+			Description: "Create view",
+		},
+		CreateDatabase: Key{
+			Runes:       []string{"+"},
+			Description: "Create database",
+		},
+		DropDatabase: Key{
+			Runes:       []string{"X"},
+			Description: "Drop database",
+		},
+		ShowProfiler: Key{
+			Runes:       []string{"F"},
+			Description: "Show profiler",
+		},
+		ShowUsers: Key{
+			Runes:       []string{"U"},
+			Description: "Show users and roles",
+		},
+		ShowValidation: Key{
+			Runes:       []string{"L"},
+			Description: "Show validation rules",
+		},
 	}
 
 	k.Content = ContentKeys{
@@ -224,6 +402,10 @@ func (k *KeyBindings) loadDefaults() {
 			Runes:       []string{"e"},
 			Description: "Edit",
 		},
+		InlineEdit: Key{
+			Runes:       []string{"i"},
+			Description: "Edit value inline",
+		},
 		DuplicateDocument: Key{
 			Runes:       []string{"d"},
 			Description: "Duplicate",
@@ -276,6 +458,69 @@ func (k *KeyBindings) loadDefaults() {
 			Runes:       []string{"b"},
 			Description: "Previous page",
 		},
+		ToggleFlatten: Key{
+			Runes:       []string{"F"},
+			Description: "Toggle nested columns",
+		},
+		ExpandArray: Key{
+			Runes:       []string{"x"},
+			Description: "Expand/collapse array",
+		},
+		ShowColumns: Key{
+			Runes:       []string{"o"},
+			Description: "Manage columns",
+		},
+		QuickFilter: Key{
+			Runes:       []string{"q"},
+			Description: "Filter by value",
+		},
+		FollowReference: Key{
+			Runes:       []string{"r"},
+			Description: "Follow reference",
+		},
+		WidenColumn: Key{
+			Runes:       []string{">"},
+			Description: "Widen column",
+		},
+		NarrowColumn: Key{
+			Runes:       []string{"<"},
+			Description: "Narrow column",
+		},
+		Undo: Key{
+			Runes:       []string{"u"},
+			Description: "Undo last change",
+		},
+		Redo: Key{
+			Keys:        []string{"Ctrl+R"},
+			Description: "Redo last undone change",
+		},
+		ShowJournal: Key{
+			Runes:       []string{"U"},
+			Description: "Show recent changes",
+		},
+		// search keys are the same in content and peeker: "/" toggles query bar,
+		// "?" shows help and "n" is the next page, so vim's search word keys
+		// are used, with ";" and "," to repeat the search
+		Search: Key{
+			Runes:       []string{"*"},
+			Description: "Search forward",
+		},
+		SearchBackward: Key{
+			Runes:       []string{"#"},
+			Description: "Search backward",
+		},
+		NextMatch: Key{
+			Runes:       []string{";"},
+			Description: "Next match",
+		},
+		PreviousMatch: Key{
+			Runes:       []string{","},
+			Description: "Previous match",
+		},
+		CopyCollection: Key{
+			Runes:       []string{"Y"},
+			Description: "Copy collection",
+		},
 	}
 
 	k.QueryBar = QueryBar{
@@ -374,6 +619,47 @@ func (k *KeyBindings) loadDefaults() {
 			Runes:       []string{"R"},
 			Description: "Refresh document",
 		},
+		FollowReference: Key{
+			Runes:       []string{"r"},
+			Description: "Follow reference",
+		},
+		Back: Key{
+			Runes:       []string{"b"},
+			Description: "Back to previous document",
+		},
+		ToggleTree: Key{
+			Runes:       []string{"t"},
+			Description: "Toggle tree view",
+		},
+		ExpandNode: Key{
+			Runes:       []string{"l"},
+			Description: "Expand node (tree view)",
+		},
+		CollapseNode: Key{
+			Runes:       []string{"h"},
+			Description: "Collapse node (tree view)",
+		},
+		CopyPath: Key{
+			Runes:       []string{"y"},
+			Description: "Copy node path (tree view)",
+		},
+		// the same search keys as in content
+		Search: Key{
+			Runes:       []string{"*"},
+			Description: "Search forward",
+		},
+		SearchBackward: Key{
+			Runes:       []string{"#"},
+			Description: "Search backward",
+		},
+		NextMatch: Key{
+			Runes:       []string{";"},
+			Description: "Next match",
+		},
+		PreviousMatch: Key{
+			Runes:       []string{","},
+			Description: "Previous match",
+		},
 	}
 
 	k.History = HistoryKeys{
@@ -390,6 +676,172 @@ func (k *KeyBindings) loadDefaults() {
 			Description: "Close history",
 		},
 	}
+
+	k.Columns = ColumnsKeys{
+		ToggleHidden: Key{
+			Keys:        []string{"Space", "Enter"},
+			Description: "Hide/show column",
+		},
+		TogglePinned: Key{
+			Runes:       []string{"p"},
+			Description: "Pin/unpin column",
+		},
+		MoveUp: Key{
+			Runes:       []string{"K"},
+			Description: "Move column left",
+		},
+		MoveDown: Key{
+			Runes:       []string{"J"},
+			Description: "Move column right",
+		},
+		CloseColumns: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"o"},
+			Description: "Close columns",
+		},
+	}
+
+	k.QuickFilter = QuickFilterKeys{
+		ApplyFilter: Key{
+			Keys:        []string{"Enter"},
+			Description: "Apply filter",
+		},
+		CloseQuickFilter: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close quick filter",
+		},
+	}
+
+	k.CommandBar = CommandBarKeys{
+		Complete: Key{
+			Keys:        []string{"Tab"},
+			Description: "Complete command",
+		},
+		PreviousCommand: Key{
+			Keys:        []string{"Up"},
+			Description: "Previous command",
+		},
+		NextCommand: Key{
+			Keys:        []string{"Down"},
+			Description: "Next command",
+		},
+	}
+
+	k.Search = SearchKeys{
+		AcceptSearch: Key{
+			Keys:        []string{"Enter"},
+			Description: "Search",
+		},
+		ToggleRegex: Key{
+			Keys:        []string{"Ctrl+R"},
+			Description: "Toggle regex",
+		},
+		CloseSearch: Key{
+			Keys:        []string{"Esc"},
+			Description: "Close search",
+		},
+	}
+
+	k.Journal = JournalKeys{
+		UndoEntry: Key{
+			Keys:        []string{"Enter"},
+			Description: "Undo selected change",
+		},
+		CloseJournal: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"U"},
+			Description: "Close journal",
+		},
+	}
+
+	k.Dashboard = DashboardKeys{
+		CloseDashboard: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close dashboard",
+		},
+	}
+
+	k.Operations = OperationsKeys{
+		FilterOperations: Key{
+			Runes:       []string{"/"},
+			Description: "Filter operations",
+		},
+		KillOperation: Key{
+			Runes:       []string{"K"},
+			Description: "Kill operation",
+		},
+		CloseOperations: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close operations",
+		},
+	}
+
+	k.Profiler = ProfilerKeys{
+		ChangeLevel: Key{
+			Runes:       []string{"L"},
+			Description: "Change profiling level",
+		},
+		Refresh: Key{
+			Runes:       []string{"R"},
+			Description: "Refresh",
+		},
+		OpenQuery: Key{
+			Keys:        []string{"Enter"},
+			Description: "Open collection with the filter",
+		},
+		CloseProfiler: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close profiler",
+		},
+	}
+
+	k.Topology = TopologyKeys{
+		CloseTopology: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close topology",
+		},
+	}
+
+	k.Users = UsersKeys{
+		CreateUser: Key{
+			Runes:       []string{"a"},
+			Description: "Create user",
+		},
+		GrantRole: Key{
+			Runes:       []string{"g"},
+			Description: "Grant roles",
+		},
+		RevokeRole: Key{
+			Runes:       []string{"r"},
+			Description: "Revoke role",
+		},
+		ChangePassword: Key{
+			Runes:       []string{"p"},
+			Description: "Change password",
+		},
+		DropUser: Key{
+			Runes:       []string{"D"},
+			Description: "Drop user",
+		},
+		SwitchFocus: Key{
+			Keys:        []string{"Tab"},
+			Description: "Switch between users and roles",
+		},
+		Refresh: Key{
+			Runes:       []string{"R"},
+			Description: "Refresh",
+		},
+		CloseUsers: Key{
+			Keys:        []string{"Esc"},
+			Runes:       []string{"q"},
+			Description: "Close users",
+		},
+	}
 }
 
 // LoadKeybindings loads keybindings from the config file
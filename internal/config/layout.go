@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LayoutsFile = "layouts.yaml"
+
+	DefaultColumnWidth = 30
+	MinColumnWidth     = 5
+	MaxColumnWidth     = 200
+)
+
+// ColumnLayout describes how columns of the collection are displayed
+// in the table view, columns are identified by field names
+type ColumnLayout struct {
+	Order  []string       `yaml:"order,omitempty"`
+	Hidden []string       `yaml:"hidden,omitempty"`
+	Pinned []string       `yaml:"pinned,omitempty"`
+	Widths map[string]int `yaml:"widths,omitempty"`
+}
+
+// Layouts holds column layouts of all collections, keyed by db.collection
+type Layouts struct {
+	Collections map[string]*ColumnLayout `yaml:"collections"`
+
+	path string
+}
+
+// GetLayoutsPath returns the path to the file with column layouts
+func GetLayoutsPath() (string, error) {
+	configPath, err := util.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s", configPath, LayoutsFile), nil
+}
+
+// NewLayouts returns empty layouts that will be saved under given path
+func NewLayouts(path string) *Layouts {
+	return &Layouts{
+		Collections: map[string]*ColumnLayout{},
+		path:        path,
+	}
+}
+
+// LoadLayouts loads column layouts from the file,
+// if the file does not exist empty layouts are returned
+func LoadLayouts(path string) (*Layouts, error) {
+	layouts := NewLayouts(path)
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return layouts, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(bytes, layouts); err != nil {
+		return nil, err
+	}
+	if layouts.Collections == nil {
+		layouts.Collections = map[string]*ColumnLayout{}
+	}
+
+	return layouts, nil
+}
+
+// Get returns the layout of the collection, it's created if it doesn't exist
+func (l *Layouts) Get(db, coll string) *ColumnLayout {
+	key := db + "." + coll
+	layout, ok := l.Collections[key]
+	if !ok {
+		layout = &ColumnLayout{}
+		l.Collections[key] = layout
+	}
+	return layout
+}
+
+// Save writes layouts to the file, empty layouts are skipped
+func (l *Layouts) Save() error {
+	for key, layout := range l.Collections {
+		if layout.isEmpty() {
+			delete(l.Collections, key)
+		}
+	}
+
+	bytes, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(l.path, bytes, 0644)
+}
+
+func (cl *ColumnLayout) isEmpty() bool {
+	return len(cl.Order) == 0 && len(cl.Hidden) == 0 && len(cl.Pinned) == 0 && len(cl.Widths) == 0
+}
+
+// Arrange returns all columns in the display order: pinned columns first,
+// then columns in saved order and new columns sorted alphabetically at the end
+func (cl *ColumnLayout) Arrange(columns []string) []string {
+	available := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		available[column] = true
+	}
+
+	arranged := []string{}
+	added := map[string]bool{}
+	add := func(column string) {
+		if available[column] && !added[column] {
+			arranged = append(arranged, column)
+			added[column] = true
+		}
+	}
+
+	for _, column := range cl.Pinned {
+		add(column)
+	}
+	for _, column := range cl.Order {
+		add(column)
+	}
+
+	rest := []string{}
+	for _, column := range columns {
+		if !added[column] {
+			rest = append(rest, column)
+		}
+	}
+	sort.Strings(rest)
+	for _, column := range rest {
+		add(column)
+	}
+
+	return arranged
+}
+
+// Visible returns arranged columns without hidden ones
+func (cl *ColumnLayout) Visible(columns []string) []string {
+	visible := []string{}
+	for _, column := range cl.Arrange(columns) {
+		if !cl.IsHidden(column) {
+			visible = append(visible, column)
+		}
+	}
+	return visible
+}
+
+// PinnedCount returns number of visible pinned columns
+func (cl *ColumnLayout) PinnedCount(columns []string) int {
+	count := 0
+	for _, column := range cl.Visible(columns) {
+		if cl.IsPinned(column) {
+			count++
+		}
+	}
+	return count
+}
+
+// SetOrder saves the order of columns
+func (cl *ColumnLayout) SetOrder(columns []string) {
+	cl.Order = append([]string{}, columns...)
+}
+
+// Move moves the column by delta positions and saves the new order,
+// pinned columns are always kept before the rest
+func (cl *ColumnLayout) Move(columns []string, column string, delta int) []string {
+	arranged := cl.Arrange(columns)
+	index := -1
+	for i, c := range arranged {
+		if c == column {
+			index = i
+		}
+	}
+	target := index + delta
+	if index < 0 || target < 0 || target >= len(arranged) {
+		return arranged
+	}
+	arranged[index], arranged[target] = arranged[target], arranged[index]
+
+	pinned := []string{}
+	for _, c := range arranged {
+		if cl.IsPinned(c) {
+			pinned = append(pinned, c)
+		}
+	}
+	for _, c := range cl.Pinned {
+		if !contains(arranged, c) {
+			pinned = append(pinned, c)
+		}
+	}
+	cl.Pinned = pinned
+	cl.SetOrder(arranged)
+
+	return cl.Arrange(columns)
+}
+
+func (cl *ColumnLayout) IsHidden(column string) bool {
+	return contains(cl.Hidden, column)
+}
+
+func (cl *ColumnLayout) IsPinned(column string) bool {
+	return contains(cl.Pinned, column)
+}
+
+// ToggleHidden hides visible column or shows hidden one
+func (cl *ColumnLayout) ToggleHidden(column string) {
+	cl.Hidden = toggle(cl.Hidden, column)
+}
+
+// TogglePinned pins the column to the left or unpins it
+func (cl *ColumnLayout) TogglePinned(column string) {
+	cl.Pinned = toggle(cl.Pinned, column)
+}
+
+// Width returns the width of the column
+func (cl *ColumnLayout) Width(column string) int {
+	if width, ok := cl.Widths[column]; ok {
+		return width
+	}
+	return DefaultColumnWidth
+}
+
+// Resize changes the width of the column by delta, keeping it within limits
+func (cl *ColumnLayout) Resize(column string, delta int) {
+	width := cl.Width(column) + delta
+	width = max(MinColumnWidth, min(MaxColumnWidth, width))
+
+	if cl.Widths == nil {
+		cl.Widths = map[string]int{}
+	}
+	if width == DefaultColumnWidth {
+		delete(cl.Widths, column)
+		return
+	}
+	cl.Widths[column] = width
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toggle(values []string, value string) []string {
+	for i, v := range values {
+		if v == value {
+			return append(values[:i:i], values[i+1:]...)
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnLayout_Arrange(t *testing.T) {
+	columns := []string{"name", "_id", "age", "email"}
+
+	layout := &ColumnLayout{}
+	assert.Equal(t, []string{"_id", "age", "email", "name"}, layout.Arrange(columns))
+
+	layout = &ColumnLayout{
+		Order:  []string{"name", "missing", "age"},
+		Pinned: []string{"email"},
+		Hidden: []string{"age"},
+	}
+	assert.Equal(t, []string{"email", "name", "age", "_id"}, layout.Arrange(columns))
+	assert.Equal(t, []string{"email", "name", "_id"}, layout.Visible(columns))
+	assert.Equal(t, 1, layout.PinnedCount(columns))
+}
+
+func TestColumnLayout_Move(t *testing.T) {
+	columns := []string{"_id", "age", "name"}
+	layout := &ColumnLayout{Pinned: []string{"name", "other"}}
+
+	assert.Equal(t, []string{"name", "age", "_id"}, layout.Move(columns, "_id", 1))
+	assert.Equal(t, []string{"name", "age", "_id"}, layout.Move(columns, "_id", 1))
+	assert.Equal(t, []string{"name", "_id", "age"}, layout.Move(columns, "_id", -1))
+	// unpinned column can't be moved before pinned one
+	assert.Equal(t, []string{"name", "_id", "age"}, layout.Move(columns, "_id", -1))
+
+	layout.TogglePinned("_id")
+	assert.Equal(t, []string{"_id", "name", "age"}, layout.Move(columns, "_id", -1))
+	assert.Equal(t, []string{"_id", "name", "other"}, layout.Pinned)
+}
+
+func TestColumnLayout_Toggle(t *testing.T) {
+	layout := &ColumnLayout{}
+
+	layout.ToggleHidden("age")
+	layout.TogglePinned("_id")
+	assert.True(t, layout.IsHidden("age"))
+	assert.True(t, layout.IsPinned("_id"))
+
+	layout.ToggleHidden("age")
+	layout.TogglePinned("_id")
+	assert.False(t, layout.IsHidden("age"))
+	assert.False(t, layout.IsPinned("_id"))
+}
+
+func TestColumnLayout_Resize(t *testing.T) {
+	layout := &ColumnLayout{}
+	assert.Equal(t, DefaultColumnWidth, layout.Width("name"))
+
+	layout.Resize("name", 10)
+	assert.Equal(t, DefaultColumnWidth+10, layout.Width("name"))
+
+	layout.Resize("name", -1000)
+	assert.Equal(t, MinColumnWidth, layout.Width("name"))
+
+	layout.Resize("name", 1000)
+	assert.Equal(t, MaxColumnWidth, layout.Width("name"))
+
+	layout.Resize("name", DefaultColumnWidth-MaxColumnWidth)
+	assert.NotContains(t, layout.Widths, "name")
+}
+
+func TestLayouts_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LayoutsFile)
+
+	layouts, err := LoadLayouts(path)
+	assert.NoError(t, err)
+	assert.Empty(t, layouts.Collections)
+
+	layout := layouts.Get("shop", "users")
+	layout.TogglePinned("_id")
+	layout.Resize("name", 10)
+	layouts.Get("shop", "orders")
+	assert.NoError(t, layouts.Save())
+
+	loaded, err := LoadLayouts(path)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Collections, 1)
+	assert.Equal(t, []string{"_id"}, loaded.Get("shop", "users").Pinned)
+	assert.Equal(t, DefaultColumnWidth+10, loaded.Get("shop", "users").Width("name"))
+}
@@ -97,14 +97,16 @@ type (
 		SelectedRowColor         Style `yaml:"selectedRowColor"`
 		SeparatorSymbol          Style `yaml:"separatorSymbol"`
 		SeparatorColor           Style `yaml:"separatorColor"`
+		SearchMatchColor         Style `yaml:"searchMatchColor"`
 	}
 
 	// DocPeekerStyle is a struct that contains all the styles for the json peeker
 	DocPeekerStyle struct {
-		KeyColor       Style `yaml:"keyColor"`
-		ValueColor     Style `yaml:"valueColor"`
-		BracketColor   Style `yaml:"bracketColor"`
-		HighlightColor Style `yaml:"highlightColor"`
+		KeyColor         Style `yaml:"keyColor"`
+		ValueColor       Style `yaml:"valueColor"`
+		BracketColor     Style `yaml:"bracketColor"`
+		HighlightColor   Style `yaml:"highlightColor"`
+		SearchMatchColor Style `yaml:"searchMatchColor"`
 	}
 
 	// InputBarStyle is a struct that contains all the styles for the filter bar
@@ -210,13 +212,15 @@ func (s *Styles) loadDefaults() {
 		SelectedRowColor:         "#4ADE80",
 		SeparatorSymbol:          "|",
 		SeparatorColor:           "#334155",
+		SearchMatchColor:         "#7C5E10",
 	}
 
 	s.DocPeeker = DocPeekerStyle{
-		KeyColor:       "#387D44",
-		ValueColor:     "#E2E8F0",
-		HighlightColor: "#3a4963",
-		BracketColor:   "#FDE68A",
+		KeyColor:         "#387D44",
+		ValueColor:       "#E2E8F0",
+		HighlightColor:   "#3a4963",
+		BracketColor:     "#FDE68A",
+		SearchMatchColor: "#7C5E10",
 	}
 
 	s.InputBar = InputBarStyle{
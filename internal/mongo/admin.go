@@ -0,0 +1,222 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CollectionTypeRegular    = "Regular"
+	CollectionTypeCapped     = "Capped"
+	CollectionTypeTimeSeries = "Time series"
+	CollectionTypeClustered  = "Clustered"
+)
+
+// CollectionTypes are types of collections that can be created
+var CollectionTypes = []string{
+	CollectionTypeRegular,
+	CollectionTypeCapped,
+	CollectionTypeTimeSeries,
+	CollectionTypeClustered,
+}
+
+// CollectionOptions are options of a new collection, only options
+// of the chosen type are used
+type CollectionOptions struct {
+	Type string
+	// Size and MaxDocuments limit capped collection
+	Size         int64
+	MaxDocuments int64
+	// TimeField, MetaField and Granularity describe time series collection
+	TimeField   string
+	MetaField   string
+	Granularity string
+	// ExpireAfterSeconds removes old documents of time series and clustered collections
+	ExpireAfterSeconds int64
+}
+
+// Validate checks if options required by the type are set
+func (o CollectionOptions) Validate() error {
+	switch o.Type {
+	case CollectionTypeRegular, CollectionTypeClustered:
+	case CollectionTypeCapped:
+		if o.Size <= 0 {
+			return fmt.Errorf("size of capped collection must be greater than 0")
+		}
+	case CollectionTypeTimeSeries:
+		if o.TimeField == "" {
+			return fmt.Errorf("time field of time series collection is required")
+		}
+		switch o.Granularity {
+		case "", "seconds", "minutes", "hours":
+		default:
+			return fmt.Errorf("granularity must be one of seconds, minutes or hours")
+		}
+	default:
+		return fmt.Errorf("unknown collection type %s", o.Type)
+	}
+	if o.Size < 0 || o.MaxDocuments < 0 || o.ExpireAfterSeconds < 0 {
+		return fmt.Errorf("numeric options cannot be negative")
+	}
+	return nil
+}
+
+func (o CollectionOptions) toCreateOptions() *options.CreateCollectionOptions {
+	opts := options.CreateCollection()
+	switch o.Type {
+	case CollectionTypeCapped:
+		opts.SetCapped(true).SetSizeInBytes(o.Size)
+		if o.MaxDocuments > 0 {
+			opts.SetMaxDocuments(o.MaxDocuments)
+		}
+	case CollectionTypeTimeSeries:
+		timeSeries := options.TimeSeries().SetTimeField(o.TimeField)
+		if o.MetaField != "" {
+			timeSeries.SetMetaField(o.MetaField)
+		}
+		if o.Granularity != "" {
+			timeSeries.SetGranularity(o.Granularity)
+		}
+		opts.SetTimeSeriesOptions(timeSeries)
+	case CollectionTypeClustered:
+		opts.SetClusteredIndex(primitive.D{
+			{Key: "key", Value: primitive.M{"_id": 1}},
+			{Key: "unique", Value: true},
+		})
+	}
+	if o.ExpireAfterSeconds > 0 && (o.Type == CollectionTypeTimeSeries || o.Type == CollectionTypeClustered) {
+		opts.SetExpireAfterSeconds(o.ExpireAfterSeconds)
+	}
+	return opts
+}
+
+// CreateCollection creates the collection with options of its type
+func (d *Dao) CreateCollection(ctx context.Context, db string, collection string, opts CollectionOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	err := d.write(AuditEntry{Operation: CreateCollectionOperation, Db: db, Collection: collection}, func(*AuditEntry) error {
+		return d.client.Database(db).CreateCollection(ctx, collection, opts.toCreateOptions())
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Collection added, db: %v, collection: %v, type: %v", db, collection, opts.Type)
+
+	return nil
+}
+
+// RenameCollection renames the collection within its database
+func (d *Dao) RenameCollection(ctx context.Context, db string, collection string, newName string) error {
+	command := primitive.D{
+		{Key: "renameCollection", Value: db + "." + collection},
+		{Key: "to", Value: db + "." + newName},
+	}
+	entry := AuditEntry{
+		Operation:  RenameCollectionOperation,
+		Db:         db,
+		Collection: collection,
+		After:      primitive.M{"to": newName},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database("admin").RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Collection renamed, db: %v, collection: %v, new name: %v", db, collection, newName)
+
+	return nil
+}
+
+// ConvertToCapped converts the collection to capped one with given size in bytes
+func (d *Dao) ConvertToCapped(ctx context.Context, db string, collection string, size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("size of capped collection must be greater than 0")
+	}
+	command := primitive.D{
+		{Key: "convertToCapped", Value: collection},
+		{Key: "size", Value: size},
+	}
+	entry := AuditEntry{
+		Operation:  ConvertToCappedOperation,
+		Db:         db,
+		Collection: collection,
+		After:      primitive.M{"size": size},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Collection converted to capped, db: %v, collection: %v, size: %v", db, collection, size)
+
+	return nil
+}
+
+// CreateView creates a view of the source collection with the pipeline
+func (d *Dao) CreateView(ctx context.Context, db string, view string, source string, pipeline primitive.A) error {
+	entry := AuditEntry{
+		Operation:  CreateViewOperation,
+		Db:         db,
+		Collection: view,
+		After:      primitive.M{"viewOn": source, "pipeline": pipeline},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).CreateView(ctx, view, source, pipeline)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("View created, db: %v, view: %v, source: %v", db, view, source)
+
+	return nil
+}
+
+// DropDatabase drops the database with all its collections
+func (d *Dao) DropDatabase(ctx context.Context, db string) error {
+	err := d.write(AuditEntry{Operation: DropDatabaseOperation, Db: db}, func(*AuditEntry) error {
+		return d.client.Database(db).Drop(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Database dropped, db: %v", db)
+
+	return nil
+}
+
+// ParsePipeline parses aggregation pipeline given as an array of stages,
+// keys don't have to be quoted as in queries. Stages are parsed as ordered
+// documents, as order of fields matters in stages like $sort
+func ParsePipeline(text string) (primitive.A, error) {
+	if text == "" {
+		return primitive.A{}, nil
+	}
+	query, err := toExtJson(fmt.Sprintf(`{"pipeline": %s}`, text))
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Pipeline []primitive.D `bson:"pipeline"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(query), true, &parsed); err != nil {
+		return nil, fmt.Errorf("pipeline must be an array of stages: %w", err)
+	}
+	pipeline := primitive.A{}
+	for _, stage := range parsed.Pipeline {
+		pipeline = append(pipeline, stage)
+	}
+	return pipeline, nil
+}
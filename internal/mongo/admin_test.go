@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCollectionOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    CollectionOptions
+		wantErr bool
+	}{
+		{"regular", CollectionOptions{Type: CollectionTypeRegular}, false},
+		{"clustered", CollectionOptions{Type: CollectionTypeClustered, ExpireAfterSeconds: 60}, false},
+		{"capped", CollectionOptions{Type: CollectionTypeCapped, Size: 1024, MaxDocuments: 10}, false},
+		{"capped without size", CollectionOptions{Type: CollectionTypeCapped}, true},
+		{"time series", CollectionOptions{Type: CollectionTypeTimeSeries, TimeField: "ts", Granularity: "hours"}, false},
+		{"time series without time field", CollectionOptions{Type: CollectionTypeTimeSeries}, true},
+		{"time series with wrong granularity", CollectionOptions{Type: CollectionTypeTimeSeries, TimeField: "ts", Granularity: "days"}, true},
+		{"negative expiration", CollectionOptions{Type: CollectionTypeClustered, ExpireAfterSeconds: -1}, true},
+		{"unknown type", CollectionOptions{Type: "Sharded"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCollectionOptionsToCreateOptions(t *testing.T) {
+	capped := CollectionOptions{Type: CollectionTypeCapped, Size: 1024, MaxDocuments: 10, ExpireAfterSeconds: 60}.toCreateOptions()
+	assert.True(t, *capped.Capped)
+	assert.Equal(t, int64(1024), *capped.SizeInBytes)
+	assert.Equal(t, int64(10), *capped.MaxDocuments)
+	assert.Nil(t, capped.ExpireAfterSeconds)
+
+	timeSeries := CollectionOptions{Type: CollectionTypeTimeSeries, TimeField: "ts", MetaField: "meta", ExpireAfterSeconds: 60}.toCreateOptions()
+	assert.Equal(t, "ts", timeSeries.TimeSeriesOptions.TimeField)
+	assert.Equal(t, "meta", *timeSeries.TimeSeriesOptions.MetaField)
+	assert.Nil(t, timeSeries.TimeSeriesOptions.Granularity)
+	assert.Equal(t, int64(60), *timeSeries.ExpireAfterSeconds)
+
+	clustered := CollectionOptions{Type: CollectionTypeClustered}.toCreateOptions()
+	assert.NotNil(t, clustered.ClusteredIndex)
+	assert.Nil(t, clustered.Capped)
+}
+
+func TestParsePipeline(t *testing.T) {
+	pipeline, err := ParsePipeline(`[{$match: {status: "active"}}, {$limit: 5}]`)
+	assert.NoError(t, err)
+	assert.Len(t, pipeline, 2)
+
+	pipeline, err = ParsePipeline("")
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.A{}, pipeline)
+
+	_, err = ParsePipeline(`{$match: {}}`)
+	assert.Error(t, err)
+
+	_, err = ParsePipeline(`[{$match: }]`)
+	assert.Error(t, err)
+
+	_, err = ParsePipeline(`[5]`)
+	assert.Error(t, err)
+}
+
+func TestParsePipelineKeepsOrder(t *testing.T) {
+	pipeline, err := ParsePipeline(`[{$sort: {b: 1, a: -1, c: 1}}, {$project: {z: 1, y: 1}}]`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, primitive.A{
+		primitive.D{{Key: "$sort", Value: primitive.D{{Key: "b", Value: int32(1)}, {Key: "a", Value: int32(-1)}, {Key: "c", Value: int32(1)}}}},
+		primitive.D{{Key: "$project", Value: primitive.D{{Key: "z", Value: int32(1)}, {Key: "y", Value: int32(1)}}}},
+	}, pipeline)
+}
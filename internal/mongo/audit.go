@@ -0,0 +1,122 @@
+package mongo
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CreateCollectionOperation OperationType = "createCollection"
+	DropCollectionOperation   OperationType = "dropCollection"
+	RenameCollectionOperation OperationType = "renameCollection"
+	ConvertToCappedOperation  OperationType = "convertToCapped"
+	CreateViewOperation       OperationType = "createView"
+	DropDatabaseOperation     OperationType = "dropDatabase"
+	CopyCollectionOperation   OperationType = "copyCollection"
+	SetValidationOperation    OperationType = "setValidation"
+	CreateUserOperation       OperationType = "createUser"
+	GrantRolesOperation       OperationType = "grantRoles"
+	RevokeRolesOperation      OperationType = "revokeRoles"
+	ChangePasswordOperation   OperationType = "changePassword"
+	DropUserOperation         OperationType = "dropUser"
+	KillOperationOperation    OperationType = "killOp"
+	SetProfilingOperation     OperationType = "setProfiling"
+)
+
+// AuditEntry is a single line of the audit log
+type AuditEntry struct {
+	Time       string        `bson:"time"`
+	Connection string        `bson:"connection"`
+	Db         string        `bson:"db"`
+	Collection string        `bson:"collection"`
+	Id         interface{}   `bson:"_id,omitempty"`
+	Operation  OperationType `bson:"operation"`
+	Before     primitive.M   `bson:"before,omitempty"`
+	After      primitive.M   `bson:"after,omitempty"`
+	// Error is set when the operation failed, some changes
+	// could be made anyway, like part of copied documents
+	Error string `bson:"error,omitempty"`
+}
+
+// AuditLog is an append-only log of write operations stored as
+// newline delimited extended JSON
+type AuditLog struct {
+	mu            sync.Mutex
+	path          string
+	excludeImages map[string]bool
+}
+
+// NewAuditLog creates audit log stored in the file under given path,
+// excludeImages is a list of namespaces (db.collection or db.* for all
+// collections in the database) for which documents are not logged
+func NewAuditLog(path string, excludeImages []string) *AuditLog {
+	excluded := make(map[string]bool, len(excludeImages))
+	for _, ns := range excludeImages {
+		excluded[ns] = true
+	}
+
+	return &AuditLog{
+		path:          path,
+		excludeImages: excluded,
+	}
+}
+
+// IncludeImages returns true if documents of the collection should be logged
+func (a *AuditLog) IncludeImages(db, coll string) bool {
+	if a == nil {
+		return false
+	}
+	return !a.excludeImages[db+"."+coll] && !a.excludeImages[db+".*"]
+}
+
+// Write appends the entry to the log, images of documents
+// are removed for excluded collections
+func (a *AuditLog) Write(entry AuditEntry) error {
+	if a == nil {
+		return nil
+	}
+
+	file, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.append(file, entry)
+}
+
+// open opens the log file for appending entries
+func (a *AuditLog) open() (*os.File, error) {
+	file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+	return file, nil
+}
+
+// append writes the entry to the opened log file
+func (a *AuditLog) append(file *os.File, entry AuditEntry) error {
+	if entry.Time == "" {
+		entry.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	if !a.IncludeImages(entry.Db, entry.Collection) {
+		entry.Before = nil
+		entry.After = nil
+	}
+
+	line, err := bson.MarshalExtJSON(entry, false, false)
+	if err != nil {
+		return fmt.Errorf("error marshaling audit entry: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
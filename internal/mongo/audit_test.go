@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kopecmaciej/vi-mongo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func readAuditLines(t *testing.T, path string) []map[string]interface{} {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestAuditLog_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit := NewAuditLog(path, nil)
+
+	err := audit.Write(AuditEntry{
+		Connection: "local",
+		Db:         "shop",
+		Collection: "users",
+		Id:         "1",
+		Operation:  UpdateOperation,
+		Before:     primitive.M{"_id": "1", "name": "old"},
+		After:      primitive.M{"_id": "1", "name": "new"},
+	})
+	assert.NoError(t, err)
+	err = audit.Write(AuditEntry{Db: "shop", Collection: "users", Id: "1", Operation: DeleteOperation})
+	assert.NoError(t, err)
+
+	lines := readAuditLines(t, path)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "local", lines[0]["connection"])
+	assert.Equal(t, "update", lines[0]["operation"])
+	assert.Equal(t, "1", lines[0]["_id"])
+	assert.NotEmpty(t, lines[0]["time"])
+	assert.Equal(t, "new", lines[0]["after"].(map[string]interface{})["name"])
+	assert.Equal(t, "delete", lines[1]["operation"])
+	assert.NotContains(t, lines[1], "before")
+}
+
+func TestAuditLog_ExcludeImages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit := NewAuditLog(path, []string{"shop.users", "secrets.*"})
+
+	assert.False(t, audit.IncludeImages("shop", "users"))
+	assert.False(t, audit.IncludeImages("secrets", "tokens"))
+	assert.True(t, audit.IncludeImages("shop", "orders"))
+
+	err := audit.Write(AuditEntry{
+		Db:         "secrets",
+		Collection: "tokens",
+		Id:         "1",
+		Operation:  InsertOperation,
+		After:      primitive.M{"_id": "1", "token": "secret"},
+	})
+	assert.NoError(t, err)
+
+	lines := readAuditLines(t, path)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "1", lines[0]["_id"])
+	assert.NotContains(t, lines[0], "after")
+}
+
+func TestAuditLog_Nil(t *testing.T) {
+	var audit *AuditLog
+	assert.NoError(t, audit.Write(AuditEntry{Operation: InsertOperation}))
+	assert.False(t, audit.IncludeImages("db", "coll"))
+}
+
+func TestDao_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	dao := NewDao(nil, &config.MongoConfig{Name: "local"}, config.LogConfig{AuditPath: path})
+
+	err := dao.write(AuditEntry{Operation: InsertOperation, Db: "shop", Collection: "users"}, func(entry *AuditEntry) error {
+		entry.Id = "1"
+		entry.After = primitive.M{"_id": "1"}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	failed := errors.New("duplicate key")
+	err = dao.write(AuditEntry{Operation: InsertOperation, Db: "shop", Collection: "users"}, func(*AuditEntry) error {
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	lines := readAuditLines(t, path)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "local", lines[0]["connection"])
+	assert.Equal(t, "1", lines[0]["_id"])
+	assert.NotContains(t, lines[0], "error")
+	assert.Equal(t, "duplicate key", lines[1]["error"])
+}
+
+func TestDao_WriteWithoutAuditLog(t *testing.T) {
+	dao := NewDao(nil, &config.MongoConfig{Name: "local"}, config.LogConfig{AuditPath: filepath.Join(t.TempDir(), "missing", "audit.log")})
+
+	called := false
+	err := dao.write(AuditEntry{Operation: DeleteOperation}, func(*AuditEntry) error {
+		called = true
+		return nil
+	})
+	assert.Error(t, err)
+	assert.False(t, called, "change must not be made if it can't be recorded")
+}
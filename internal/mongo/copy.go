@@ -0,0 +1,176 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// defaultCopyBatchSize is the number of documents inserted at once
+	defaultCopyBatchSize = 1000
+)
+
+// CopyOptions are options of copying a collection
+type CopyOptions struct {
+	// Filter limits copied documents, all documents are copied if it's empty
+	Filter      primitive.M
+	BatchSize   int
+	CopyIndexes bool
+	// DropTarget drops the target collection before copying
+	DropTarget bool
+}
+
+// CopyProgress is reported after every copied batch
+type CopyProgress struct {
+	Copied int64
+	Total  int64
+}
+
+// CopyCollection copies documents of the collection to the target collection
+// of the target dao, which may be connected to other server, progress is
+// called after every inserted batch and copying stops when ctx is cancelled
+func (d *Dao) CopyCollection(ctx context.Context, db, collection string, target *Dao, targetDb, targetColl string, opts CopyOptions, progress func(CopyProgress)) error {
+	if target == d && db == targetDb && collection == targetColl {
+		return fmt.Errorf("target collection must be different from the source")
+	}
+	if opts.Filter == nil {
+		opts.Filter = primitive.M{}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultCopyBatchSize
+	}
+
+	source := d.client.Database(db).Collection(collection)
+	total, err := source.CountDocuments(ctx, opts.Filter)
+	if err != nil {
+		return err
+	}
+
+	entry := AuditEntry{
+		Operation:  CopyCollectionOperation,
+		Db:         targetDb,
+		Collection: targetColl,
+	}
+	copyProgress := CopyProgress{Total: total}
+	err = target.write(entry, func(entry *AuditEntry) error {
+		err := d.copyDocuments(ctx, source, target, targetDb, targetColl, opts, &copyProgress, progress)
+		// documents copied before an error are recorded too
+		entry.After = primitive.M{"from": db + "." + collection, "documents": copyProgress.Copied}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Collection copied, from: %v.%v, to: %v.%v, documents: %v", db, collection, targetDb, targetColl, copyProgress.Copied)
+
+	return nil
+}
+
+// copyDocuments drops the target collection if needed, copies indexes
+// and inserts documents of the source in batches, counting copied ones
+func (d *Dao) copyDocuments(ctx context.Context, source *mongo.Collection, target *Dao, targetDb, targetColl string, opts CopyOptions, copyProgress *CopyProgress, progress func(CopyProgress)) error {
+	destination := target.client.Database(targetDb).Collection(targetColl)
+	if opts.DropTarget {
+		if err := destination.Drop(ctx); err != nil {
+			return err
+		}
+	}
+
+	if opts.CopyIndexes {
+		if err := d.copyIndexes(ctx, source.Database().Name(), source.Name(), target, targetDb, targetColl); err != nil {
+			return err
+		}
+	}
+
+	cursor, err := source.Find(ctx, opts.Filter, options.Find().SetBatchSize(int32(opts.BatchSize)))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]interface{}, 0, opts.BatchSize)
+	insertBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := destination.InsertMany(ctx, batch); err != nil {
+			return err
+		}
+		copyProgress.Copied += int64(len(batch))
+		batch = batch[:0]
+		progress(*copyProgress)
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var document primitive.D
+		if err := cursor.Decode(&document); err != nil {
+			return err
+		}
+		batch = append(batch, document)
+		if len(batch) == opts.BatchSize {
+			if err := insertBatch(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return insertBatch()
+}
+
+// copyIndexes creates indexes of the collection on the target collection
+func (d *Dao) copyIndexes(ctx context.Context, db, collection string, target *Dao, targetDb, targetColl string) error {
+	cursor, err := d.client.Database(db).Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	// indexes are decoded as ordered documents, as the order of
+	// fields in key of compound index matters
+	var indexes []primitive.D
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	specs := indexesToCopy(indexes)
+	if len(specs) == 0 {
+		return nil
+	}
+	command := primitive.D{
+		{Key: "createIndexes", Value: targetColl},
+		{Key: "indexes", Value: specs},
+	}
+	return target.client.Database(targetDb).RunCommand(ctx, command).Err()
+}
+
+// indexesToCopy returns specifications of indexes that can be created on
+// other collection, default _id index and fields bound to the source
+// collection are skipped
+func indexesToCopy(indexes []primitive.D) primitive.A {
+	specs := primitive.A{}
+	for _, index := range indexes {
+		spec := primitive.D{}
+		isDefault := false
+		for _, elem := range index {
+			switch elem.Key {
+			case "v", "ns":
+			case "name":
+				isDefault = elem.Value == "_id_"
+				spec = append(spec, elem)
+			default:
+				spec = append(spec, elem)
+			}
+		}
+		if !isDefault {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIndexesToCopy(t *testing.T) {
+	indexes := []primitive.D{
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: primitive.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}},
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: primitive.D{{Key: "email", Value: int32(1)}}}, {Key: "name", Value: "email_1"},
+			{Key: "unique", Value: true}, {Key: "ns", Value: "db.users"}},
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: primitive.D{{Key: "createdAt", Value: int32(1)}}}, {Key: "name", Value: "createdAt_1"},
+			{Key: "expireAfterSeconds", Value: int32(3600)}},
+	}
+
+	assert.Equal(t, primitive.A{
+		primitive.D{{Key: "key", Value: primitive.D{{Key: "email", Value: int32(1)}}}, {Key: "name", Value: "email_1"}, {Key: "unique", Value: true}},
+		primitive.D{{Key: "key", Value: primitive.D{{Key: "createdAt", Value: int32(1)}}}, {Key: "name", Value: "createdAt_1"},
+			{Key: "expireAfterSeconds", Value: int32(3600)}},
+	}, indexesToCopy(indexes))
+}
+
+func TestIndexesToCopyCompoundKey(t *testing.T) {
+	key := primitive.D{{Key: "b", Value: int32(-1)}, {Key: "a", Value: int32(1)}, {Key: "c", Value: "text"}}
+	indexes := []primitive.D{
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: key}, {Key: "name", Value: "b_-1_a_1_c_text"}},
+	}
+
+	assert.Equal(t, primitive.A{
+		primitive.D{{Key: "key", Value: key}, {Key: "name", Value: "b_-1_a_1_c_text"}},
+	}, indexesToCopy(indexes))
+}
+
+func TestIndexesToCopyOnlyDefault(t *testing.T) {
+	indexes := []primitive.D{
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: primitive.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}},
+	}
+
+	assert.Empty(t, indexesToCopy(indexes))
+}
@@ -2,27 +2,44 @@ package mongo
 
 import (
 	"context"
-	"reflect"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/kopecmaciej/vi-mongo/internal/config"
 
 	"github.com/rs/zerolog/log"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrDocumentNotFound is returned when the document that should be modified
+	// does not exist anymore
+	ErrDocumentNotFound = errors.New("document not found, it may have been deleted")
+	// ErrDocumentConflict is returned when the document was changed by someone else
+	// after it was read, so the update would overwrite those changes
+	ErrDocumentConflict = errors.New("document was modified by someone else after it was loaded")
+)
+
 type Dao struct {
-	client *mongo.Client
-	Config *config.MongoConfig
+	client   *mongo.Client
+	Config   *config.MongoConfig
+	auditLog *AuditLog
 }
 
-func NewDao(client *mongo.Client, config *config.MongoConfig) *Dao {
-	return &Dao{
+// NewDao creates dao of the connection, all write operations made through
+// it are logged to the audit log if its path is set in logConfig
+func NewDao(client *mongo.Client, config *config.MongoConfig, logConfig config.LogConfig) *Dao {
+	dao := &Dao{
 		client: client,
 		Config: config,
 	}
+	if logConfig.AuditPath != "" {
+		dao.auditLog = NewAuditLog(logConfig.AuditPath, logConfig.AuditExcludeImages)
+	}
+	return dao
 }
 
 func (d *Dao) Ping(ctx context.Context) error {
@@ -60,33 +77,27 @@ func (d *Dao) GetLiveSessions(ctx context.Context) (int64, error) {
 	return int64(len(sessions)), nil
 }
 
+// DBsWithCollections is a database with its collections, collections of
+// databases are listed lazily, so they are set only when Loaded is true
 type DBsWithCollections struct {
 	DB          string
 	Collections []string
+	Loaded      bool
 }
 
-func (d *Dao) ListDbsWithCollections(ctx context.Context, nameRegex string) ([]DBsWithCollections, error) {
-	dbCollMap := []DBsWithCollections{}
-
+// ListDatabases returns names of databases matching the regex
+func (d *Dao) ListDatabases(ctx context.Context, nameRegex string) ([]string, error) {
 	filter := primitive.M{}
 	if nameRegex != "" {
 		filter = primitive.M{"name": primitive.Regex{Pattern: nameRegex, Options: "i"}}
 	}
 
-	dbs, err := d.client.ListDatabaseNames(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, db := range dbs {
-		colls, err := d.client.Database(db).ListCollectionNames(ctx, primitive.M{})
-		if err != nil {
-			return nil, err
-		}
-		dbCollMap = append(dbCollMap, DBsWithCollections{DB: db, Collections: colls})
-	}
+	return d.client.ListDatabaseNames(ctx, filter)
+}
 
-	return dbCollMap, nil
+// ListCollections returns names of collections of the database
+func (d *Dao) ListCollections(ctx context.Context, db string) ([]string, error) {
+	return d.client.Database(db).ListCollectionNames(ctx, primitive.M{})
 }
 
 type Filter struct {
@@ -131,7 +142,23 @@ func (d *Dao) ListDocuments(ctx context.Context, state *CollectionState, filter
 	return documents, count, nil
 }
 
-func (d *Dao) GetDocument(ctx context.Context, db string, collection string, id primitive.ObjectID) (primitive.M, error) {
+// FindDocuments returns all documents matching the filter, without paging
+func (d *Dao) FindDocuments(ctx context.Context, db string, collection string, filter primitive.M, sort primitive.M) ([]primitive.M, error) {
+	cursor, err := d.client.Database(db).Collection(collection).Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	documents := []primitive.M{}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (d *Dao) GetDocument(ctx context.Context, db string, collection string, id interface{}) (primitive.M, error) {
 	var document primitive.M
 	err := d.client.Database(db).Collection(collection).FindOne(ctx, primitive.M{"_id": id}).Decode(&document)
 	if err != nil {
@@ -140,77 +167,123 @@ func (d *Dao) GetDocument(ctx context.Context, db string, collection string, id
 	return document, nil
 }
 
-func (d *Dao) InsetDocument(ctx context.Context, db string, collection string, document primitive.M) (interface{}, error) {
-	res, err := d.client.Database(db).Collection(collection).InsertOne(ctx, document)
+// GetFieldValue returns the value of the document field, key can be a dotted
+// path. Embedded documents are returned as primitive.D with fields in the
+// stored order, so they can be compared in filters
+func (d *Dao) GetFieldValue(ctx context.Context, db string, collection string, id interface{}, key string) (interface{}, error) {
+	raw, err := d.client.Database(db).Collection(collection).FindOne(ctx, primitive.M{"_id": id}).Raw()
 	if err != nil {
 		return nil, err
 	}
+	rawValue, err := raw.LookupErr(strings.Split(key, ".")...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting field %s: %w", key, err)
+	}
 
-	log.Debug().Msgf("Document inserted, document: %v, db: %v, collection: %v", document, db, collection)
-
-	return res.InsertedID, nil
+	var value interface{}
+	if err := rawValue.Unmarshal(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
 }
 
-func (d *Dao) UpdateDocument(ctx context.Context, db string, collection string, id interface{}, originalDoc, document primitive.M) error {
-	setOps := bson.M{}
-	unsetOps := bson.M{}
-
-	for key, value := range document {
-		if origValue, exists := originalDoc[key]; !exists || !reflect.DeepEqual(origValue, value) {
-			setOps[key] = value
+func (d *Dao) InsetDocument(ctx context.Context, db string, collection string, document primitive.M) (interface{}, error) {
+	var insertedID interface{}
+	err := d.write(AuditEntry{Operation: InsertOperation, Db: db, Collection: collection}, func(entry *AuditEntry) error {
+		res, err := d.client.Database(db).Collection(collection).InsertOne(ctx, document)
+		if err != nil {
+			return err
 		}
-	}
+		insertedID = res.InsertedID
 
-	for key := range originalDoc {
-		if _, exists := document[key]; !exists {
-			unsetOps[key] = 1
+		entry.Id = res.InsertedID
+		entry.After = primitive.M{"_id": res.InsertedID}
+		for key, value := range document {
+			entry.After[key] = value
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	update := bson.M{}
-	if len(setOps) > 0 {
-		update["$set"] = setOps
-	}
-	if len(unsetOps) > 0 {
-		update["$unset"] = unsetOps
-	}
+	log.Debug().Msgf("Document inserted, document: %v, db: %v, collection: %v", document, db, collection)
 
+	return insertedID, nil
+}
+
+// UpdateDocument updates fields that differ between originalDoc and document.
+// The update is applied only if the document in the database still has the same
+// values of modified fields (or the same version if versionField is configured),
+// otherwise ErrDocumentConflict is returned.
+// UpdateDocument applies the changes between originalDoc and document and
+// returns the document as it's stored after the update, nil is returned
+// if there are no changes
+func (d *Dao) UpdateDocument(ctx context.Context, db string, collection string, id interface{}, originalDoc, document primitive.M) (primitive.M, error) {
+	versionField := d.Config.VersionField
+	update := BuildVersionedUpdate(originalDoc, document, versionField)
 	if len(update) == 0 {
-		return nil
+		return nil, nil
 	}
+	filter := BuildConcurrencyFilter(id, originalDoc, update, versionField)
+
+	coll := d.client.Database(db).Collection(collection)
+	var updated primitive.M
+	err := d.write(AuditEntry{Operation: UpdateOperation, Db: db, Collection: collection, Id: id}, func(entry *AuditEntry) error {
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			count, err := coll.CountDocuments(ctx, primitive.M{"_id": id})
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrDocumentNotFound
+			}
+			return ErrDocumentConflict
+		}
+		if err != nil {
+			log.Error().Msgf("Error updating document: %v", err)
+			return err
+		}
 
-	updated, err := d.client.Database(db).Collection(collection).UpdateOne(ctx, primitive.M{"_id": id}, update)
+		entry.Before = revertUpdate(updated, originalDoc, update)
+		entry.After = updated
+		return nil
+	})
 	if err != nil {
-		log.Error().Msgf("Error updating document: %v", err)
-		return err
-	}
-
-	if updated.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return nil, err
 	}
 
 	log.Debug().Msgf("Document updated, id: %v, document: %v, db: %v, collection: %v", id, document, db, collection)
 
-	return nil
+	return updated, nil
 }
 
-func (d *Dao) DeleteDocument(ctx context.Context, db string, collection string, id interface{}) error {
-	deleted, err := d.client.Database(db).Collection(collection).DeleteOne(ctx, primitive.M{"_id": id})
+// DeleteDocument deletes the document and returns it as it was stored
+func (d *Dao) DeleteDocument(ctx context.Context, db string, collection string, id interface{}) (primitive.M, error) {
+	var deleted primitive.M
+	err := d.write(AuditEntry{Operation: DeleteOperation, Db: db, Collection: collection, Id: id}, func(entry *AuditEntry) error {
+		err := d.client.Database(db).Collection(collection).FindOneAndDelete(ctx, primitive.M{"_id": id}).Decode(&deleted)
+		if err != nil {
+			return err
+		}
+		entry.Before = deleted
+		return nil
+	})
 	if err != nil {
-		return err
-	}
-
-	if deleted.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+		return nil, err
 	}
 
 	log.Debug().Msgf("Document deleted, id: %v, db: %v, collection: %v", id, db, collection)
 
-	return nil
+	return deleted, nil
 }
 
 func (d *Dao) AddCollection(ctx context.Context, db string, collection string) error {
-	err := d.client.Database(db).CreateCollection(ctx, collection)
+	err := d.write(AuditEntry{Operation: CreateCollectionOperation, Db: db, Collection: collection}, func(*AuditEntry) error {
+		return d.client.Database(db).CreateCollection(ctx, collection)
+	})
 	if err != nil {
 		return err
 	}
@@ -221,7 +294,9 @@ func (d *Dao) AddCollection(ctx context.Context, db string, collection string) e
 }
 
 func (d *Dao) DeleteCollection(ctx context.Context, db string, collection string) error {
-	err := d.client.Database(db).Collection(collection).Drop(ctx)
+	err := d.write(AuditEntry{Operation: DropCollectionOperation, Db: db, Collection: collection}, func(*AuditEntry) error {
+		return d.client.Database(db).Collection(collection).Drop(ctx)
+	})
 	if err != nil {
 		return err
 	}
@@ -241,6 +316,34 @@ func (d *Dao) ForceClose(ctx context.Context) error {
 	return nil
 }
 
+// write runs the operation that changes data on the server. All changes made
+// through the dao go through it, so every change is recorded in the audit log,
+// failed operations are recorded with the error. The log is opened before
+// the change, so nothing is changed if it can't be recorded. The operation
+// fills images and other details of the entry
+func (d *Dao) write(entry AuditEntry, operation func(entry *AuditEntry) error) error {
+	if d.auditLog == nil {
+		return operation(&entry)
+	}
+
+	file, err := d.auditLog.open()
+	if err != nil {
+		return fmt.Errorf("change was not made, as it can't be recorded: %w", err)
+	}
+	defer file.Close()
+
+	operationErr := operation(&entry)
+	if operationErr != nil {
+		entry.Error = operationErr.Error()
+	}
+
+	entry.Connection = d.Config.Name
+	if err := d.auditLog.append(file, entry); err != nil && operationErr == nil {
+		return fmt.Errorf("change was made, but it was not recorded in audit log: %w", err)
+	}
+	return operationErr
+}
+
 func (d *Dao) runAdminCommand(ctx context.Context, key string, value interface{}) (primitive.M, error) {
 	results := primitive.M{}
 	command := primitive.D{{Key: key, Value: value}}
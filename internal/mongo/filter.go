@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FilterEquals      = "$eq"
+	FilterNotEquals   = "$ne"
+	FilterGreaterThan = "$gt"
+	FilterLessThan    = "$lt"
+	FilterExists      = "$exists"
+	FilterNotExists   = "$notExists"
+)
+
+// BuildCondition returns condition on the field, value keeps its BSON type
+// so ObjectIDs and dates are compared as such. Embedded documents must be
+// primitive.D, as the server compares them including the order of fields
+func BuildCondition(key, operator string, value interface{}) (primitive.M, error) {
+	switch operator {
+	case FilterEquals, FilterNotEquals, FilterGreaterThan, FilterLessThan:
+		if _, ok := value.(primitive.M); ok {
+			return nil, fmt.Errorf("embedded document of %s must keep the order of fields", key)
+		}
+		if operator == FilterEquals {
+			return primitive.M{key: value}, nil
+		}
+		return primitive.M{key: primitive.M{operator: value}}, nil
+	case FilterExists:
+		return primitive.M{key: primitive.M{"$exists": true}}, nil
+	case FilterNotExists:
+		return primitive.M{key: primitive.M{"$exists": false}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported filter operator: %s", operator)
+	}
+}
+
+// MergeFilter adds the condition to the filter string, if the filter is not empty
+// both are combined with $and. Returned filter is relaxed extended JSON
+// that can be parsed with ParseStringQuery
+func MergeFilter(filter string, condition primitive.M) (string, error) {
+	existing, err := ParseFilter(filter)
+	if err != nil {
+		return "", err
+	}
+
+	var merged primitive.M
+	switch {
+	case len(existing) == 0:
+		merged = condition
+	case len(existing) == 1 && existing["$and"] != nil:
+		conditions, ok := existing["$and"].(primitive.A)
+		if !ok {
+			return "", fmt.Errorf("invalid $and in filter: %s", filter)
+		}
+		merged = primitive.M{"$and": append(conditions, condition)}
+	default:
+		merged = primitive.M{"$and": primitive.A{existing, condition}}
+	}
+
+	extJson, err := bson.MarshalExtJSON(merged, false, false)
+	if err != nil {
+		return "", err
+	}
+
+	return string(extJson), nil
+}
@@ -0,0 +1,112 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		value    interface{}
+		expected primitive.M
+		wantErr  bool
+	}{
+		{"Equals", FilterEquals, "John", primitive.M{"name": "John"}, false},
+		{"Not equals", FilterNotEquals, "John", primitive.M{"name": primitive.M{"$ne": "John"}}, false},
+		{"Greater than", FilterGreaterThan, int32(5), primitive.M{"name": primitive.M{"$gt": int32(5)}}, false},
+		{"Less than", FilterLessThan, int32(5), primitive.M{"name": primitive.M{"$lt": int32(5)}}, false},
+		{"Exists", FilterExists, nil, primitive.M{"name": primitive.M{"$exists": true}}, false},
+		{"Not exists", FilterNotExists, nil, primitive.M{"name": primitive.M{"$exists": false}}, false},
+		{"Embedded document", FilterEquals, primitive.D{{Key: "first", Value: "John"}}, primitive.M{"name": primitive.D{{Key: "first", Value: "John"}}}, false},
+		{"Unordered embedded document", FilterEquals, primitive.M{"first": "John"}, nil, true},
+		{"Exists on embedded document", FilterExists, primitive.M{"first": "John"}, primitive.M{"name": primitive.M{"$exists": true}}, false},
+		{"Unknown", "$regex", "J", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildCondition("name", tt.operator, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMergeFilter(t *testing.T) {
+	objectId, _ := primitive.ObjectIDFromHex("5f1a4b3c2d1e0f0a0b0c0d0e")
+	date := primitive.NewDateTimeFromTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name      string
+		filter    string
+		condition primitive.M
+		expected  primitive.M
+	}{
+		{
+			name:      "Empty filter",
+			filter:    "",
+			condition: primitive.M{"_id": objectId},
+			expected:  primitive.M{"_id": objectId},
+		},
+		{
+			name:      "Existing filter",
+			filter:    `{ name: "John" }`,
+			condition: primitive.M{"created": primitive.M{"$gt": date}},
+			expected: primitive.M{"$and": primitive.A{
+				primitive.M{"name": "John"},
+				primitive.M{"created": primitive.M{"$gt": date}},
+			}},
+		},
+		{
+			name:      "Existing $and",
+			filter:    `{ "$and": [{ name: "John" }] }`,
+			condition: primitive.M{"age": primitive.M{"$lt": int32(30)}},
+			expected: primitive.M{"$and": primitive.A{
+				primitive.M{"name": "John"},
+				primitive.M{"age": primitive.M{"$lt": int32(30)}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeFilter(tt.filter, tt.condition)
+			assert.NoError(t, err)
+
+			parsed, err := ParseStringQuery(merged)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, primitive.M(parsed))
+		})
+	}
+}
+
+func TestMergeFilter_EmbeddedDocumentOrder(t *testing.T) {
+	address := primitive.D{{Key: "street", Value: "Main"}, {Key: "city", Value: "Warsaw"}}
+
+	merged, err := MergeFilter("", primitive.M{"address": address})
+	assert.NoError(t, err)
+	// merging again must not reorder the embedded document of the existing filter
+	merged, err = MergeFilter(merged, primitive.M{"age": int32(30)})
+	assert.NoError(t, err)
+
+	parsed, err := ParseFilter(merged)
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.M{"$and": primitive.A{
+		primitive.D{{Key: "address", Value: address}},
+		primitive.D{{Key: "age", Value: int32(30)}},
+	}}, parsed)
+}
+
+func TestMergeFilter_InvalidFilter(t *testing.T) {
+	_, err := MergeFilter("{ name: ", primitive.M{"age": 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxJournalEntries = 100
+)
+
+type OperationType string
+
+const (
+	InsertOperation OperationType = "insert"
+	UpdateOperation OperationType = "update"
+	DeleteOperation OperationType = "delete"
+)
+
+// JournalEntry describes a single write operation, Before is the full
+// document before the operation (nil for insert) and After the document
+// after the operation (nil for delete)
+type JournalEntry struct {
+	Type   OperationType
+	Db     string
+	Coll   string
+	Id     interface{}
+	Before primitive.M
+	After  primitive.M
+	Time   time.Time
+}
+
+// String returns short description of the entry
+func (e JournalEntry) String() string {
+	return fmt.Sprintf("%s %s %s.%s _id: %s", e.Time.Format(time.TimeOnly), e.Type, e.Db, e.Coll, StringifyId(e.Id))
+}
+
+// Journal keeps track of write operations made during the session
+// so they can be undone and redone
+type Journal struct {
+	mu   sync.Mutex
+	undo []JournalEntry
+	redo []JournalEntry
+}
+
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Record adds new operation to the journal, it clears operations
+// that can be redone as they are no longer valid
+func (j *Journal) Record(entry JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	j.undo = appendLimited(j.undo, entry)
+	j.redo = nil
+}
+
+// Entries returns operations that can be undone, starting from the most recent one
+func (j *Journal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]JournalEntry, len(j.undo))
+	for i, entry := range j.undo {
+		entries[len(j.undo)-1-i] = entry
+	}
+	return entries
+}
+
+// TakeUndo removes and returns the operation to undo, index is counted
+// from the most recent operation as returned by Entries
+func (j *Journal) TakeUndo(index int) (JournalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entry JournalEntry
+	var ok bool
+	j.undo, entry, ok = take(j.undo, index)
+	return entry, ok
+}
+
+// TakeRedo removes and returns the most recently undone operation
+func (j *Journal) TakeRedo() (JournalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entry JournalEntry
+	var ok bool
+	j.redo, entry, ok = take(j.redo, 0)
+	return entry, ok
+}
+
+// PushUndo puts the operation back to the list of operations that can be undone
+// without clearing the redo list
+func (j *Journal) PushUndo(entry JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.undo = appendLimited(j.undo, entry)
+}
+
+// RestoreUndo puts the operation that failed to be undone back at the index
+// it was taken from with TakeUndo, so the order of operations is kept
+func (j *Journal) RestoreUndo(index int, entry JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	position := min(max(len(j.undo)-index, 0), len(j.undo))
+	j.undo = slices.Insert(j.undo, position, entry)
+	if len(j.undo) > maxJournalEntries {
+		j.undo = j.undo[len(j.undo)-maxJournalEntries:]
+	}
+}
+
+// PushRedo adds undone operation to the list of operations that can be redone
+func (j *Journal) PushRedo(entry JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.redo = appendLimited(j.redo, entry)
+}
+
+func appendLimited(entries []JournalEntry, entry JournalEntry) []JournalEntry {
+	entries = append(entries, entry)
+	if len(entries) > maxJournalEntries {
+		entries = entries[len(entries)-maxJournalEntries:]
+	}
+	return entries
+}
+
+// take removes entry from the stack, index 0 is the top of the stack
+func take(entries []JournalEntry, index int) ([]JournalEntry, JournalEntry, bool) {
+	position := len(entries) - 1 - index
+	if index < 0 || position < 0 {
+		return entries, JournalEntry{}, false
+	}
+	entry := entries[position]
+	rest := append([]JournalEntry{}, entries[:position]...)
+	return append(rest, entries[position+1:]...), entry, true
+}
+
+// UndoOperation reverts the operation described by the entry. Returned entry
+// holds documents as they are stored now, e.g. with incremented version,
+// so the operation can be redone
+func (d *Dao) UndoOperation(ctx context.Context, entry JournalEntry) (JournalEntry, error) {
+	switch entry.Type {
+	case InsertOperation:
+		_, err := d.DeleteDocument(ctx, entry.Db, entry.Coll, entry.Id)
+		return entry, err
+	case DeleteOperation:
+		_, err := d.InsetDocument(ctx, entry.Db, entry.Coll, entry.Before)
+		return entry, err
+	case UpdateOperation:
+		reverted, err := d.UpdateDocument(ctx, entry.Db, entry.Coll, entry.Id, withoutId(entry.After), withoutId(entry.Before))
+		if reverted != nil {
+			entry.Before = reverted
+		}
+		return entry, err
+	default:
+		return entry, fmt.Errorf("unknown operation type: %s", entry.Type)
+	}
+}
+
+// RedoOperation applies the operation described by the entry again. Returned
+// entry holds documents as they are stored now, so it can be undone again
+func (d *Dao) RedoOperation(ctx context.Context, entry JournalEntry) (JournalEntry, error) {
+	switch entry.Type {
+	case InsertOperation:
+		_, err := d.InsetDocument(ctx, entry.Db, entry.Coll, entry.After)
+		return entry, err
+	case DeleteOperation:
+		deleted, err := d.DeleteDocument(ctx, entry.Db, entry.Coll, entry.Id)
+		if deleted != nil {
+			entry.Before = deleted
+		}
+		return entry, err
+	case UpdateOperation:
+		updated, err := d.UpdateDocument(ctx, entry.Db, entry.Coll, entry.Id, withoutId(entry.Before), withoutId(entry.After))
+		if updated != nil {
+			entry.After = updated
+		}
+		return entry, err
+	default:
+		return entry, fmt.Errorf("unknown operation type: %s", entry.Type)
+	}
+}
+
+func withoutId(doc primitive.M) primitive.M {
+	copied := deepCopy(doc)
+	delete(copied, "_id")
+	return copied
+}
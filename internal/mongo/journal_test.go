@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal_UndoRedo(t *testing.T) {
+	j := NewJournal()
+	j.Record(JournalEntry{Type: InsertOperation, Id: "1"})
+	j.Record(JournalEntry{Type: UpdateOperation, Id: "2"})
+
+	entries := j.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "2", entries[0].Id)
+	assert.False(t, entries[0].Time.IsZero())
+
+	entry, ok := j.TakeUndo(0)
+	assert.True(t, ok)
+	assert.Equal(t, "2", entry.Id)
+	j.PushRedo(entry)
+
+	entry, ok = j.TakeRedo()
+	assert.True(t, ok)
+	assert.Equal(t, "2", entry.Id)
+	j.PushUndo(entry)
+
+	_, ok = j.TakeRedo()
+	assert.False(t, ok)
+	assert.Len(t, j.Entries(), 2)
+}
+
+func TestJournal_TakeUndoByIndex(t *testing.T) {
+	j := NewJournal()
+	j.Record(JournalEntry{Id: "1"})
+	j.Record(JournalEntry{Id: "2"})
+	j.Record(JournalEntry{Id: "3"})
+
+	entry, ok := j.TakeUndo(1)
+	assert.True(t, ok)
+	assert.Equal(t, "2", entry.Id)
+
+	entries := j.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "3", entries[0].Id)
+	assert.Equal(t, "1", entries[1].Id)
+
+	_, ok = j.TakeUndo(5)
+	assert.False(t, ok)
+}
+
+func TestJournal_RestoreUndo(t *testing.T) {
+	j := NewJournal()
+	j.Record(JournalEntry{Id: "1"})
+	j.Record(JournalEntry{Id: "2"})
+	j.Record(JournalEntry{Id: "3"})
+
+	entry, ok := j.TakeUndo(1)
+	assert.True(t, ok)
+	j.RestoreUndo(1, entry)
+
+	entries := j.Entries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "3", entries[0].Id)
+	assert.Equal(t, "2", entries[1].Id)
+	assert.Equal(t, "1", entries[2].Id)
+
+	entry, ok = j.TakeUndo(2)
+	assert.True(t, ok)
+	j.RestoreUndo(2, entry)
+	assert.Equal(t, entries, j.Entries())
+}
+
+func TestJournal_RecordClearsRedo(t *testing.T) {
+	j := NewJournal()
+	j.Record(JournalEntry{Id: "1"})
+	entry, _ := j.TakeUndo(0)
+	j.PushRedo(entry)
+
+	j.Record(JournalEntry{Id: "2"})
+
+	_, ok := j.TakeRedo()
+	assert.False(t, ok)
+}
+
+func TestJournal_Limit(t *testing.T) {
+	j := NewJournal()
+	for i := 0; i < maxJournalEntries+10; i++ {
+		j.Record(JournalEntry{Id: i})
+	}
+
+	entries := j.Entries()
+	assert.Len(t, entries, maxJournalEntries)
+	assert.Equal(t, maxJournalEntries+9, entries[0].Id)
+}
@@ -0,0 +1,483 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SchemaViolation is a single place where a document doesn't match $jsonSchema
+type SchemaViolation struct {
+	// Path is a dotted path of the field, empty for the document itself
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return "document: " + v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// JsonSchema is $jsonSchema of the collection validator together with
+// the level at which the server enforces it
+type JsonSchema struct {
+	Schema primitive.M
+	Level  string
+}
+
+// Validate validates the document that will be stored, existing is the
+// document it replaces or nil for inserts. With moderate level the server
+// doesn't validate updates of documents that are already invalid, so such
+// updates are not validated either
+func (s *JsonSchema) Validate(existing primitive.M, document primitive.M) []SchemaViolation {
+	if s == nil {
+		return nil
+	}
+	if s.Level == "moderate" && existing != nil && len(ValidateJsonSchema(s.Schema, existing)) > 0 {
+		return nil
+	}
+	return ValidateJsonSchema(s.Schema, document)
+}
+
+// GetJsonSchema returns $jsonSchema of the collection validator, nil is
+// returned if there is none or if the server would not reject invalid
+// documents because validation is off or its action is warn
+func (d *Dao) GetJsonSchema(ctx context.Context, db string, collection string) (*JsonSchema, error) {
+	validation, err := d.GetCollectionValidation(ctx, db, collection)
+	if err != nil {
+		return nil, err
+	}
+	if validation.Level == "off" || validation.Action != "error" {
+		return nil, nil
+	}
+	for _, elem := range validation.Validator {
+		if elem.Key != "$jsonSchema" {
+			continue
+		}
+		if schema, ok := asDocument(elem.Value); ok {
+			return &JsonSchema{Schema: schema, Level: validation.Level}, nil
+		}
+	}
+	return nil, nil
+}
+
+// ValidateJsonSchema validates the document against $jsonSchema supported
+// by MongoDB, unsupported keywords are ignored. Violations are sorted by path
+func ValidateJsonSchema(schema primitive.M, document primitive.M) []SchemaViolation {
+	violations := validateSchemaValue(schema, document, "")
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations
+}
+
+func validateSchemaValue(schema primitive.M, value interface{}, path string) []SchemaViolation {
+	violations := []SchemaViolation{}
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if bsonTypes, ok := schemaTypes(schema["bsonType"]); ok && !matchesAnyType(value, bsonTypes, bsonTypeOf) {
+		violate("expected bsonType %s, got %s", strings.Join(bsonTypes, " or "), bsonTypeOf(value))
+		return violations
+	}
+	if jsonTypes, ok := schemaTypes(schema["type"]); ok && !matchesAnyType(value, jsonTypes, jsonTypeOf) {
+		violate("expected type %s, got %s", strings.Join(jsonTypes, " or "), jsonTypeOf(value))
+		return violations
+	}
+
+	if enum, ok := asArray(schema["enum"]); ok && !containsValue(enum, value) {
+		violate("value must be one of %s", formatValues(enum))
+	}
+
+	if number, ok := asNumber(value); ok {
+		if minimum, ok := asNumber(schema["minimum"]); ok {
+			if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && number <= minimum {
+				violate("must be greater than %v", minimum)
+			} else if number < minimum {
+				violate("must be greater than or equal to %v", minimum)
+			}
+		}
+		if maximum, ok := asNumber(schema["maximum"]); ok {
+			if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && number >= maximum {
+				violate("must be less than %v", maximum)
+			} else if number > maximum {
+				violate("must be less than or equal to %v", maximum)
+			}
+		}
+		if multipleOf, ok := asNumber(schema["multipleOf"]); ok && multipleOf != 0 {
+			if quotient := number / multipleOf; quotient != math.Trunc(quotient) {
+				violate("must be a multiple of %v", multipleOf)
+			}
+		}
+	}
+
+	if text, ok := value.(string); ok {
+		length := float64(utf8.RuneCountInString(text))
+		if minLength, ok := asNumber(schema["minLength"]); ok && length < minLength {
+			violate("length must be at least %v", minLength)
+		}
+		if maxLength, ok := asNumber(schema["maxLength"]); ok && length > maxLength {
+			violate("length must be at most %v", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(text) {
+				violate("must match pattern %s", pattern)
+			}
+		}
+	}
+
+	if doc, ok := asDocument(value); ok {
+		violations = append(violations, validateSchemaObject(schema, doc, path)...)
+	}
+	if array, ok := asArray(value); ok {
+		violations = append(violations, validateSchemaArray(schema, array, path)...)
+	}
+
+	if allOf, ok := asArray(schema["allOf"]); ok {
+		for _, s := range allOf {
+			if sub, ok := asDocument(s); ok {
+				violations = append(violations, validateSchemaValue(sub, value, path)...)
+			}
+		}
+	}
+	if anyOf, ok := asArray(schema["anyOf"]); ok && countMatching(anyOf, value, path) == 0 {
+		violate("must match at least one schema of anyOf")
+	}
+	if oneOf, ok := asArray(schema["oneOf"]); ok && countMatching(oneOf, value, path) != 1 {
+		violate("must match exactly one schema of oneOf")
+	}
+	if not, ok := asDocument(schema["not"]); ok && len(validateSchemaValue(not, value, path)) == 0 {
+		violate("must not match the schema of not")
+	}
+
+	return violations
+}
+
+func validateSchemaObject(schema primitive.M, doc map[string]interface{}, path string) []SchemaViolation {
+	violations := []SchemaViolation{}
+
+	if required, ok := asArray(schema["required"]); ok {
+		for _, r := range required {
+			if field, ok := r.(string); ok {
+				if _, present := doc[field]; !present {
+					violations = append(violations, SchemaViolation{Path: joinPath(path, field), Message: "field is required"})
+				}
+			}
+		}
+	}
+
+	count := float64(len(doc))
+	if minProperties, ok := asNumber(schema["minProperties"]); ok && count < minProperties {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at least %v fields", minProperties)})
+	}
+	if maxProperties, ok := asNumber(schema["maxProperties"]); ok && count > maxProperties {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at most %v fields", maxProperties)})
+	}
+
+	properties, _ := asDocument(schema["properties"])
+	patterns, _ := asDocument(schema["patternProperties"])
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := joinPath(path, key)
+		matched := false
+		if property, ok := asDocument(properties[key]); ok {
+			matched = true
+			violations = append(violations, validateSchemaValue(property, doc[key], fieldPath)...)
+		} else if _, ok := properties[key]; ok {
+			matched = true
+		}
+		for pattern, s := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil || !re.MatchString(key) {
+				continue
+			}
+			matched = true
+			if sub, ok := asDocument(s); ok {
+				violations = append(violations, validateSchemaValue(sub, doc[key], fieldPath)...)
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				violations = append(violations, SchemaViolation{Path: fieldPath, Message: "field is not allowed"})
+			}
+		default:
+			if sub, ok := asDocument(additional); ok {
+				violations = append(violations, validateSchemaValue(sub, doc[key], fieldPath)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func validateSchemaArray(schema primitive.M, array []interface{}, path string) []SchemaViolation {
+	violations := []SchemaViolation{}
+
+	count := float64(len(array))
+	if minItems, ok := asNumber(schema["minItems"]); ok && count < minItems {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at least %v items", minItems)})
+	}
+	if maxItems, ok := asNumber(schema["maxItems"]); ok && count > maxItems {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must have at most %v items", maxItems)})
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range array {
+			if containsValue(array[:i], array[i]) {
+				violations = append(violations, SchemaViolation{Path: path, Message: "items must be unique"})
+				break
+			}
+		}
+	}
+
+	itemPath := func(i int) string {
+		return joinPath(path, fmt.Sprintf("%d", i))
+	}
+	if items, ok := asDocument(schema["items"]); ok {
+		for i, item := range array {
+			violations = append(violations, validateSchemaValue(items, item, itemPath(i))...)
+		}
+	} else if items, ok := asArray(schema["items"]); ok {
+		for i, item := range array {
+			if i < len(items) {
+				if sub, ok := asDocument(items[i]); ok {
+					violations = append(violations, validateSchemaValue(sub, item, itemPath(i))...)
+				}
+				continue
+			}
+			if additional, ok := schema["additionalItems"].(bool); ok && !additional {
+				violations = append(violations, SchemaViolation{Path: itemPath(i), Message: "item is not allowed"})
+			} else if sub, ok := asDocument(schema["additionalItems"]); ok {
+				violations = append(violations, validateSchemaValue(sub, item, itemPath(i))...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func countMatching(schemas []interface{}, value interface{}, path string) int {
+	count := 0
+	for _, s := range schemas {
+		if sub, ok := asDocument(s); ok && len(validateSchemaValue(sub, value, path)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// schemaTypes returns types of bsonType or type keyword, which can
+// be a single type or an array of types
+func schemaTypes(value interface{}) ([]string, bool) {
+	if t, ok := value.(string); ok {
+		return []string{t}, true
+	}
+	array, ok := asArray(value)
+	if !ok {
+		return nil, false
+	}
+	types := []string{}
+	for _, t := range array {
+		if s, ok := t.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types, true
+}
+
+func matchesAnyType(value interface{}, types []string, typeOf func(interface{}) string) bool {
+	actual := typeOf(value)
+	for _, t := range types {
+		if t == actual {
+			return true
+		}
+		// number is an alias of all numeric bson types
+		if t == "number" && isNumeric(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// bsonTypeOf returns the BSON type alias of the value
+func bsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, float32:
+		return "double"
+	case int32, int8, int16, uint8, uint16:
+		return "int"
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return "int"
+		}
+		return "long"
+	case int64, uint32:
+		return "long"
+	case primitive.Decimal128:
+		return "decimal"
+	case primitive.ObjectID:
+		return "objectId"
+	case primitive.DateTime, time.Time:
+		return "date"
+	case primitive.Timestamp:
+		return "timestamp"
+	case primitive.Regex:
+		return "regex"
+	case primitive.Binary, []byte:
+		return "binData"
+	case primitive.JavaScript:
+		return "javascript"
+	case primitive.MinKey:
+		return "minKey"
+	case primitive.MaxKey:
+		return "maxKey"
+	}
+	if _, ok := asDocument(value); ok {
+		return "object"
+	}
+	if _, ok := asArray(value); ok {
+		return "array"
+	}
+	return reflect.TypeOf(value).String()
+}
+
+// jsonTypeOf returns the JSON schema type of the value
+func jsonTypeOf(value interface{}) string {
+	switch t := bsonTypeOf(value); {
+	case t == "bool":
+		return "boolean"
+	case isNumeric(value):
+		return "number"
+	default:
+		return t
+	}
+}
+
+func isNumeric(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int8, int16, int32, int64, uint8, uint16, uint32, primitive.Decimal128:
+		return true
+	}
+	return false
+}
+
+func asNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// asDocument converts documents decoded in any of supported forms to a map
+func asDocument(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case primitive.M:
+		return v, true
+	case map[string]interface{}:
+		return v, true
+	case primitive.D:
+		doc := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			doc[elem.Key] = elem.Value
+		}
+		return doc, true
+	}
+	return nil, false
+}
+
+func asArray(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case primitive.A:
+		return v, true
+	case []interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// containsValue checks if values contain the value, numbers
+// are compared by value regardless of their type
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if schemaValuesEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaValuesEqual(a, b interface{}) bool {
+	if x, ok := asNumber(a); ok {
+		y, ok := asNumber(b)
+		return ok && x == y
+	}
+	if x, ok := asDocument(a); ok {
+		y, ok := asDocument(b)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !schemaValuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	if x, ok := asArray(a); ok {
+		y, ok := asArray(b)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !schemaValuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		formatted = append(formatted, fmt.Sprintf("%v", v))
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
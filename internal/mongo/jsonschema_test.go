@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateJsonSchema(t *testing.T) {
+	schema := primitive.M{
+		"bsonType":             "object",
+		"required":             primitive.A{"name", "email"},
+		"additionalProperties": false,
+		"properties": primitive.M{
+			"_id":   primitive.M{"bsonType": "objectId"},
+			"name":  primitive.M{"bsonType": "string", "minLength": int32(2)},
+			"email": primitive.M{"bsonType": "string", "pattern": "^.+@.+$"},
+			"age":   primitive.M{"bsonType": primitive.A{"int", "long"}, "minimum": int32(0), "maximum": int32(150)},
+			"role":  primitive.M{"enum": primitive.A{"admin", "user"}},
+			"tags": primitive.M{
+				"bsonType":    "array",
+				"maxItems":    int32(2),
+				"uniqueItems": true,
+				"items":       primitive.M{"bsonType": "string"},
+			},
+			"address": primitive.M{
+				"bsonType": "object",
+				"required": primitive.A{"city"},
+			},
+		},
+	}
+
+	valid := primitive.M{
+		"_id":     primitive.NewObjectID(),
+		"name":    "Jane",
+		"email":   "jane@example.com",
+		"age":     int64(30),
+		"role":    "admin",
+		"tags":    primitive.A{"a", "b"},
+		"address": primitive.M{"city": "Warsaw"},
+	}
+	assert.Empty(t, ValidateJsonSchema(schema, valid))
+
+	invalid := primitive.M{
+		"name":    "J",
+		"age":     3.5,
+		"role":    "guest",
+		"tags":    primitive.A{"a", int64(1), "a"},
+		"address": primitive.M{},
+		"extra":   true,
+	}
+	assert.Equal(t, []SchemaViolation{
+		{Path: "address.city", Message: "field is required"},
+		{Path: "age", Message: "expected bsonType int or long, got double"},
+		{Path: "email", Message: "field is required"},
+		{Path: "extra", Message: "field is not allowed"},
+		{Path: "name", Message: "length must be at least 2"},
+		{Path: "role", Message: "value must be one of [admin, user]"},
+		{Path: "tags", Message: "must have at most 2 items"},
+		{Path: "tags", Message: "items must be unique"},
+		{Path: "tags.1", Message: "expected bsonType string, got long"},
+	}, ValidateJsonSchema(schema, invalid))
+}
+
+func TestValidateJsonSchemaWithDocumentSchema(t *testing.T) {
+	// validators read from the server are decoded as primitive.D
+	schema := primitive.M{
+		"required": primitive.A{"qty"},
+		"properties": primitive.D{
+			{Key: "qty", Value: primitive.D{{Key: "bsonType", Value: "number"}, {Key: "minimum", Value: int32(1)}}},
+		},
+	}
+
+	assert.Empty(t, ValidateJsonSchema(schema, primitive.M{"qty": 2.5}))
+	assert.Equal(t, []SchemaViolation{
+		{Path: "qty", Message: "must be greater than or equal to 1"},
+	}, ValidateJsonSchema(schema, primitive.M{"qty": int64(0)}))
+	assert.Equal(t, []SchemaViolation{
+		{Path: "qty", Message: "expected bsonType number, got string"},
+	}, ValidateJsonSchema(schema, primitive.M{"qty": "1"}))
+}
+
+func TestValidateJsonSchemaCombinators(t *testing.T) {
+	schema := primitive.M{
+		"properties": primitive.M{
+			"value": primitive.M{
+				"anyOf": primitive.A{primitive.M{"type": "string"}, primitive.M{"type": "number"}},
+				"not":   primitive.M{"enum": primitive.A{"forbidden"}},
+			},
+			"kind": primitive.M{
+				"oneOf": primitive.A{primitive.M{"type": "string"}, primitive.M{"maxLength": int32(3)}},
+			},
+		},
+	}
+
+	assert.Empty(t, ValidateJsonSchema(schema, primitive.M{"value": int64(5), "kind": "long"}))
+	assert.Equal(t, []SchemaViolation{
+		{Path: "kind", Message: "must match exactly one schema of oneOf"},
+		{Path: "value", Message: "must match at least one schema of anyOf"},
+	}, ValidateJsonSchema(schema, primitive.M{"value": true, "kind": "abc"}))
+	assert.Equal(t, []SchemaViolation{
+		{Path: "value", Message: "must not match the schema of not"},
+	}, ValidateJsonSchema(schema, primitive.M{"value": "forbidden"}))
+}
+
+func TestSchemaViolationString(t *testing.T) {
+	assert.Equal(t, "a.b: field is required", SchemaViolation{Path: "a.b", Message: "field is required"}.String())
+	assert.Equal(t, "document: must have at least 1 fields", SchemaViolation{Message: "must have at least 1 fields"}.String())
+}
+
+func TestJsonSchemaValidate(t *testing.T) {
+	schema := primitive.M{
+		"required":   primitive.A{"name"},
+		"properties": primitive.M{"name": primitive.M{"bsonType": "string"}},
+	}
+	invalid := primitive.M{"name": int32(1)}
+	valid := primitive.M{"name": "Jane"}
+	violations := []SchemaViolation{{Path: "name", Message: "expected bsonType string, got int"}}
+
+	strict := &JsonSchema{Schema: schema, Level: "strict"}
+	assert.Equal(t, violations, strict.Validate(nil, invalid))
+	assert.Equal(t, violations, strict.Validate(invalid, invalid))
+	assert.Empty(t, strict.Validate(invalid, valid))
+
+	moderate := &JsonSchema{Schema: schema, Level: "moderate"}
+	assert.Equal(t, violations, moderate.Validate(nil, invalid), "inserts are always validated")
+	assert.Equal(t, violations, moderate.Validate(valid, invalid))
+	assert.Empty(t, moderate.Validate(invalid, primitive.M{"name": false}), "already invalid documents are not validated")
+
+	var none *JsonSchema
+	assert.Empty(t, none.Validate(nil, invalid))
+}
@@ -1,23 +1,47 @@
 package mongo
 
+import "time"
+
 type ServerStatus struct {
-	Ok             int32  `bson:"ok"`
-	Version        string `bson:"version"`
-	Uptime         int32  `bson:"uptime"`
-	CurrentConns   int32  `bson:"connections.current"`
-	AvailableConns int32  `bson:"connections.available"`
-	OpCounters     struct {
-		Insert int32 `bson:"insert"`
-		Query  int32 `bson:"query"`
-		Update int32 `bson:"update"`
-		Delete int32 `bson:"delete"`
-	} `bson:"opcounters"`
-	Mem struct {
-		Resident int32 `bson:"resident"`
-		Virtual  int32 `bson:"virtual"`
+	Ok          int32     `bson:"ok"`
+	Host        string    `bson:"host"`
+	Version     string    `bson:"version"`
+	Uptime      int64     `bson:"uptime"`
+	LocalTime   time.Time `bson:"localTime"`
+	Connections struct {
+		Current      int64 `bson:"current"`
+		Available    int64 `bson:"available"`
+		TotalCreated int64 `bson:"totalCreated"`
+	} `bson:"connections"`
+	OpCounters OpCounters `bson:"opcounters"`
+	Mem        struct {
+		Resident int64 `bson:"resident"`
+		Virtual  int64 `bson:"virtual"`
 	} `bson:"mem"`
+	Network struct {
+		BytesIn     int64 `bson:"bytesIn"`
+		BytesOut    int64 `bson:"bytesOut"`
+		NumRequests int64 `bson:"numRequests"`
+	} `bson:"network"`
+	WiredTiger struct {
+		Cache struct {
+			BytesInCache int64 `bson:"bytes currently in the cache"`
+			MaxBytes     int64 `bson:"maximum bytes configured"`
+			DirtyBytes   int64 `bson:"tracked dirty bytes in the cache"`
+		} `bson:"cache"`
+	} `bson:"wiredTiger"`
 	Repl struct {
-		ReadOnly bool `bson:"readOnly"`
-		IsMaster bool `bson:"ismaster"`
+		SetName  string `bson:"setName"`
+		ReadOnly bool   `bson:"readOnly"`
+		IsMaster bool   `bson:"ismaster"`
 	} `bson:"repl"`
 }
+
+type OpCounters struct {
+	Insert  int64 `bson:"insert"`
+	Query   int64 `bson:"query"`
+	Update  int64 `bson:"update"`
+	Delete  int64 `bson:"delete"`
+	GetMore int64 `bson:"getmore"`
+	Command int64 `bson:"command"`
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Operation is an in-progress operation reported by currentOp command
+type Operation struct {
+	// OpID is a number, or a string prefixed with the shard name on mongos
+	OpID        interface{}
+	Namespace   string
+	Type        string
+	SecsRunning int64
+	Client      string
+	PlanSummary string
+	// Command is the command of the operation as extended JSON
+	Command string
+}
+
+// GetCurrentOperations returns active operations of the server
+func (d *Dao) GetCurrentOperations(ctx context.Context) ([]Operation, error) {
+	result, err := d.runAdminCommand(ctx, "currentOp", 1)
+	if err != nil {
+		return nil, err
+	}
+	return parseOperations(result), nil
+}
+
+// KillOperation terminates the operation with given id
+func (d *Dao) KillOperation(ctx context.Context, opID interface{}) error {
+	command := primitive.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opID}}
+	entry := AuditEntry{
+		Operation: KillOperationOperation,
+		Db:        "admin",
+		After:     primitive.M{"opid": opID},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database("admin").RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Operation killed, opid: %v", opID)
+
+	return nil
+}
+
+// FilterOperations returns operations which namespace contains the namespace
+// and which are running at least minSecs seconds
+func FilterOperations(operations []Operation, namespace string, minSecs int64) []Operation {
+	filtered := []Operation{}
+	for _, op := range operations {
+		if namespace != "" && !strings.Contains(op.Namespace, namespace) {
+			continue
+		}
+		if op.SecsRunning < minSecs {
+			continue
+		}
+		filtered = append(filtered, op)
+	}
+	return filtered
+}
+
+func parseOperations(result primitive.M) []Operation {
+	inprog, _ := result["inprog"].(primitive.A)
+
+	operations := []Operation{}
+	for _, item := range inprog {
+		op, ok := item.(primitive.M)
+		if !ok {
+			continue
+		}
+		operation := Operation{
+			OpID:        op["opid"],
+			SecsRunning: toInt64(op["secs_running"]),
+		}
+		operation.Namespace, _ = op["ns"].(string)
+		operation.Type, _ = op["op"].(string)
+		operation.Client, _ = op["client"].(string)
+		operation.PlanSummary, _ = op["planSummary"].(string)
+		if command, ok := op["command"]; ok {
+			if jsoned, err := bson.MarshalExtJSON(primitive.M{"command": command}, false, false); err == nil {
+				operation.Command = strings.TrimSuffix(strings.TrimPrefix(string(jsoned), `{"command":`), "}")
+			}
+		}
+		operations = append(operations, operation)
+	}
+	return operations
+}
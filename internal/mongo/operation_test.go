@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseOperations(t *testing.T) {
+	result := primitive.M{
+		"inprog": primitive.A{
+			primitive.M{
+				"opid":         int32(12),
+				"ns":           "shop.orders",
+				"op":           "query",
+				"secs_running": int64(5),
+				"client":       "127.0.0.1:5000",
+				"planSummary":  "COLLSCAN",
+				"command":      primitive.M{"find": "orders"},
+			},
+			primitive.M{
+				"opid": "shard01:34",
+				"op":   "none",
+			},
+		},
+	}
+
+	assert.Equal(t, []Operation{
+		{
+			OpID:        int32(12),
+			Namespace:   "shop.orders",
+			Type:        "query",
+			SecsRunning: 5,
+			Client:      "127.0.0.1:5000",
+			PlanSummary: "COLLSCAN",
+			Command:     `{"find":"orders"}`,
+		},
+		{OpID: "shard01:34", Type: "none"},
+	}, parseOperations(result))
+}
+
+func TestFilterOperations(t *testing.T) {
+	operations := []Operation{
+		{OpID: 1, Namespace: "shop.orders", SecsRunning: 10},
+		{OpID: 2, Namespace: "shop.users", SecsRunning: 1},
+		{OpID: 3, Namespace: "admin.$cmd", SecsRunning: 20},
+	}
+
+	assert.Equal(t, operations, FilterOperations(operations, "", 0))
+	assert.Equal(t, []Operation{operations[0], operations[1]}, FilterOperations(operations, "shop", 0))
+	assert.Equal(t, []Operation{operations[0], operations[2]}, FilterOperations(operations, "", 5))
+	assert.Equal(t, []Operation{operations[0]}, FilterOperations(operations, "orders", 5))
+	assert.Empty(t, FilterOperations(operations, "missing", 0))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kopecmaciej/vi-mongo/internal/util"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -69,14 +70,9 @@ func ParseStringQuery(query string) (map[string]interface{}, error) {
 		return map[string]interface{}{}, nil
 	}
 
-	query = util.QuoteUnquotedKeys(query)
-
-	query = strings.ReplaceAll(query, "ObjectID(\"", "{\"$oid\": \"")
-	query = strings.ReplaceAll(query, "\")", "\"}")
-
-	query, err := util.ParseDateToBson(query)
+	query, err := toExtJson(query)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing date: %w", err)
+		return nil, err
 	}
 
 	var filter primitive.M
@@ -88,6 +84,52 @@ func ParseStringQuery(query string) (map[string]interface{}, error) {
 	return filter, nil
 }
 
+// ParseFilter parses the filter like ParseStringQuery, but embedded documents
+// are decoded as primitive.D, as the server compares them including the order
+// of fields. It should be used for filters that are sent to the server
+func ParseFilter(query string) (primitive.M, error) {
+	if query == "" {
+		return primitive.M{}, nil
+	}
+
+	query, err := toExtJson(query)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := bsonrw.NewExtJSONValueReader(strings.NewReader(query), true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing filter %s: %w", query, err)
+	}
+	decoder, err := bson.NewDecoder(vr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing filter %s: %w", query, err)
+	}
+	decoder.DefaultDocumentD()
+
+	var filter primitive.M
+	err = decoder.Decode(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing filter %s: %w", query, err)
+	}
+
+	return filter, nil
+}
+
+// toExtJson converts query written in shell-like syntax to extended JSON
+func toExtJson(query string) (string, error) {
+	query = util.QuoteUnquotedKeys(query)
+
+	query = strings.ReplaceAll(query, "ObjectID(\"", "{\"$oid\": \"")
+	query = strings.ReplaceAll(query, "\")", "\"}")
+
+	query, err := util.ParseDateToBson(query)
+	if err != nil {
+		return "", fmt.Errorf("error parsing date: %w", err)
+	}
+	return query, nil
+}
+
 // IndentJson indents a JSON string and returns a a buffer
 func IndentJson(jsonString string) (bytes.Buffer, error) {
 	var prettyJson bytes.Buffer
@@ -64,6 +64,53 @@ func TestParseStringQuery(t *testing.T) {
 	}
 }
 
+func TestParseFilter(t *testing.T) {
+	objectID, err := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	assert.NoError(t, err, "Failed to create ObjectID for testing")
+
+	cases := []struct {
+		name     string
+		input    string
+		expected primitive.M
+		hasError bool
+	}{
+		{
+			name:     "Empty input",
+			input:    "",
+			expected: primitive.M{},
+		},
+		{
+			name:     "Embedded document keeps order of fields",
+			input:    `{ _id: ObjectID("507f1f77bcf86cd799439011"), user: { name: "John", age: 30 } }`,
+			expected: primitive.M{"_id": objectID, "user": primitive.D{{Key: "name", Value: "John"}, {Key: "age", Value: int32(30)}}},
+		},
+		{
+			name:  "Operators in $and",
+			input: `{ $and: [{ age: { $gt: 18 } }] }`,
+			expected: primitive.M{"$and": primitive.A{
+				primitive.D{{Key: "age", Value: primitive.D{{Key: "$gt", Value: int32(18)}}}},
+			}},
+		},
+		{
+			name:     "Invalid input",
+			input:    `{ name: `,
+			hasError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseFilter(tc.input)
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
 func TestParseJsonToBson(t *testing.T) {
 	objectID, err := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
 	assert.NoError(t, err, "Failed to create ObjectID for testing")
@@ -0,0 +1,126 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ProfilingStatus is the profiling level of a database, operations slower
+// than SlowMs are profiled on level 1, all operations on level 2
+type ProfilingStatus struct {
+	Level  int64
+	SlowMs int64
+}
+
+// ProfileEntry is a profiled operation stored in system.profile collection
+type ProfileEntry struct {
+	Time         time.Time
+	Namespace    string
+	Op           string
+	Millis       int64
+	DocsExamined int64
+	KeysExamined int64
+	PlanSummary  string
+	// Filter is the filter of the profiled query as extended JSON,
+	// empty if the operation has no filter
+	Filter string
+}
+
+// GetProfilingStatus returns profiling level of the database
+func (d *Dao) GetProfilingStatus(ctx context.Context, db string) (*ProfilingStatus, error) {
+	var result primitive.M
+	err := d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "profile", Value: -1}}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfilingStatus{
+		Level:  toInt64(result["was"]),
+		SlowMs: toInt64(result["slowms"]),
+	}, nil
+}
+
+// SetProfilingStatus changes profiling level and slow operation
+// threshold of the database
+func (d *Dao) SetProfilingStatus(ctx context.Context, db string, status ProfilingStatus) error {
+	if status.Level < 0 || status.Level > 2 {
+		return fmt.Errorf("profiling level must be 0, 1 or 2")
+	}
+	command := primitive.D{{Key: "profile", Value: status.Level}, {Key: "slowms", Value: status.SlowMs}}
+	entry := AuditEntry{
+		Operation: SetProfilingOperation,
+		Db:        db,
+		After:     primitive.M{"level": status.Level, "slowms": status.SlowMs},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Profiling level changed, db: %v, level: %v, slowms: %v", db, status.Level, status.SlowMs)
+
+	return nil
+}
+
+// GetProfileEntries returns the most recent profiled operations of the database
+func (d *Dao) GetProfileEntries(ctx context.Context, db string, limit int64) ([]ProfileEntry, error) {
+	opts := options.Find().SetSort(primitive.M{"ts": -1}).SetLimit(limit)
+	cursor, err := d.client.Database(db).Collection("system.profile").Find(ctx, primitive.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []primitive.M
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	entries := make([]ProfileEntry, 0, len(documents))
+	for _, doc := range documents {
+		entries = append(entries, parseProfileEntry(doc))
+	}
+	return entries, nil
+}
+
+// SplitNamespace splits db.collection namespace, collection may contain dots
+func SplitNamespace(namespace string) (string, string) {
+	db, coll, _ := strings.Cut(namespace, ".")
+	return db, coll
+}
+
+func parseProfileEntry(doc primitive.M) ProfileEntry {
+	entry := ProfileEntry{
+		Millis:       toInt64(doc["millis"]),
+		DocsExamined: toInt64(doc["docsExamined"]),
+		KeysExamined: toInt64(doc["keysExamined"]),
+	}
+	entry.Namespace, _ = doc["ns"].(string)
+	entry.Op, _ = doc["op"].(string)
+	entry.PlanSummary, _ = doc["planSummary"].(string)
+	if ts, ok := doc["ts"].(primitive.DateTime); ok {
+		entry.Time = ts.Time()
+	}
+
+	command, _ := doc["command"].(primitive.M)
+	for _, key := range []string{"filter", "q", "query"} {
+		filter, ok := command[key]
+		if !ok {
+			continue
+		}
+		if jsoned, err := bson.MarshalExtJSON(filter, false, false); err == nil {
+			entry.Filter = string(jsoned)
+		}
+		break
+	}
+
+	return entry
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseProfileEntry(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := primitive.M{
+		"ts":           primitive.NewDateTimeFromTime(ts),
+		"ns":           "shop.orders",
+		"op":           "query",
+		"millis":       int32(120),
+		"docsExamined": int32(5000),
+		"keysExamined": int64(0),
+		"planSummary":  "COLLSCAN",
+		"command":      primitive.M{"find": "orders", "filter": primitive.M{"status": "new"}},
+	}
+
+	assert.Equal(t, ProfileEntry{
+		Time:         primitive.NewDateTimeFromTime(ts).Time(),
+		Namespace:    "shop.orders",
+		Op:           "query",
+		Millis:       120,
+		DocsExamined: 5000,
+		PlanSummary:  "COLLSCAN",
+		Filter:       `{"status":"new"}`,
+	}, parseProfileEntry(doc))
+}
+
+func TestParseProfileEntryOfUpdate(t *testing.T) {
+	doc := primitive.M{
+		"ns":      "shop.orders",
+		"op":      "update",
+		"command": primitive.M{"q": primitive.M{"qty": primitive.M{"$gt": int32(5)}}, "u": primitive.M{"$set": primitive.M{"big": true}}},
+	}
+
+	entry := parseProfileEntry(doc)
+	assert.Equal(t, `{"qty":{"$gt":5}}`, entry.Filter)
+	assert.True(t, entry.Time.IsZero())
+}
+
+func TestParseProfileEntryWithoutFilter(t *testing.T) {
+	entry := parseProfileEntry(primitive.M{"op": "command", "command": primitive.M{"ping": int32(1)}})
+	assert.Equal(t, "", entry.Filter)
+}
+
+func TestSplitNamespace(t *testing.T) {
+	db, coll := SplitNamespace("shop.orders.archive")
+	assert.Equal(t, "shop", db)
+	assert.Equal(t, "orders.archive", coll)
+
+	db, coll = SplitNamespace("admin")
+	assert.Equal(t, "admin", db)
+	assert.Equal(t, "", coll)
+}
@@ -0,0 +1,150 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrReferenceNotFound is returned when referenced document can't be found
+var ErrReferenceNotFound = errors.New("referenced document not found")
+
+var objectIdRegex = regexp.MustCompile(`\b[0-9a-fA-F]{24}\b`)
+
+// Reference is a document found by following ObjectID value
+type Reference struct {
+	Db       string
+	Coll     string
+	Document primitive.M
+}
+
+// ExtractObjectID returns the first ObjectID found in the text,
+// e.g. in a line of JSON document
+func ExtractObjectID(text string) (primitive.ObjectID, bool) {
+	match := objectIdRegex.FindString(text)
+	if match == "" {
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(match)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// FindFieldPath returns dotted path of the field that holds the value,
+// if there are many such fields the one named as key is preferred
+func FindFieldPath(doc primitive.M, key string, value interface{}) string {
+	paths := []string{}
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch t := v.(type) {
+		case primitive.M:
+			for k, nested := range t {
+				walk(joinPath(path, k), nested)
+			}
+		case map[string]interface{}:
+			for k, nested := range t {
+				walk(joinPath(path, k), nested)
+			}
+		case primitive.D:
+			for _, e := range t {
+				walk(joinPath(path, e.Key), e.Value)
+			}
+		case primitive.A:
+			for i, nested := range t {
+				walk(joinPath(path, strconv.Itoa(i)), nested)
+			}
+		default:
+			if v == value {
+				paths = append(paths, path)
+			}
+		}
+	}
+	walk("", doc)
+
+	if len(paths) == 0 {
+		return ""
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		if segments[len(segments)-1] == key {
+			return path
+		}
+	}
+	return paths[0]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// ResolveReferenceTarget returns the collection configured for the field.
+// References are keyed by db.collection.field (array indexes are skipped in the field path)
+// and point to a collection in the same database or to db.collection
+func ResolveReferenceTarget(references map[string]string, db, coll, field string) (string, string, bool) {
+	segments := []string{}
+	for _, segment := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(segment); err != nil {
+			segments = append(segments, segment)
+		}
+	}
+
+	target, ok := references[db+"."+coll+"."+strings.Join(segments, ".")]
+	if !ok || target == "" {
+		return "", "", false
+	}
+	if targetDb, targetColl, found := strings.Cut(target, "."); found {
+		return targetDb, targetColl, true
+	}
+	return db, target, true
+}
+
+// FindReference looks for the document with _id equal to the id, using configured
+// references or, if the field is not configured, searching collections of the database
+func (d *Dao) FindReference(ctx context.Context, db, coll, field string, id primitive.ObjectID) (*Reference, error) {
+	if targetDb, targetColl, ok := ResolveReferenceTarget(d.Config.References, db, coll, field); ok {
+		doc, err := d.GetDocument(ctx, targetDb, targetColl, id)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w in %s.%s", ErrReferenceNotFound, targetDb, targetColl)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Reference{Db: targetDb, Coll: targetColl, Document: doc}, nil
+	}
+
+	colls, err := d.client.Database(db).ListCollectionNames(ctx, primitive.M{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(colls)
+
+	for _, target := range colls {
+		// document's own _id would always point to itself
+		if target == coll && field == "_id" || strings.HasPrefix(target, "system.") {
+			continue
+		}
+		doc, err := d.GetDocument(ctx, db, target, id)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Reference{Db: db, Coll: target, Document: doc}, nil
+	}
+
+	return nil, fmt.Errorf("%w in database %s", ErrReferenceNotFound, db)
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExtractObjectID(t *testing.T) {
+	id, ok := ExtractObjectID(`"author": { "$oid": "5f1a4b3c2d1e0f0a0b0c0d0e" },`)
+	assert.True(t, ok)
+	assert.Equal(t, "5f1a4b3c2d1e0f0a0b0c0d0e", id.Hex())
+
+	_, ok = ExtractObjectID(`"name": "John"`)
+	assert.False(t, ok)
+}
+
+func TestFindFieldPath(t *testing.T) {
+	id := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+	doc := primitive.M{
+		"_id":    other,
+		"author": id,
+		"meta": primitive.M{
+			"editor": id,
+		},
+		"tags": primitive.A{other, primitive.M{"owner": id}},
+	}
+
+	assert.Equal(t, "author", FindFieldPath(doc, "author", id))
+	assert.Equal(t, "meta.editor", FindFieldPath(doc, "editor", id))
+	assert.Equal(t, "tags.1.owner", FindFieldPath(doc, "owner", id))
+	// key that doesn't match any field returns first path
+	assert.Equal(t, "author", FindFieldPath(doc, "$oid", id))
+	assert.Equal(t, "", FindFieldPath(doc, "author", primitive.NewObjectID()))
+}
+
+func TestResolveReferenceTarget(t *testing.T) {
+	references := map[string]string{
+		"shop.orders.userId":       "users",
+		"shop.orders.items.itemId": "catalog.items",
+	}
+
+	db, coll, ok := ResolveReferenceTarget(references, "shop", "orders", "userId")
+	assert.True(t, ok)
+	assert.Equal(t, "shop", db)
+	assert.Equal(t, "users", coll)
+
+	db, coll, ok = ResolveReferenceTarget(references, "shop", "orders", "items.2.itemId")
+	assert.True(t, ok)
+	assert.Equal(t, "catalog", db)
+	assert.Equal(t, "items", coll)
+
+	_, _, ok = ResolveReferenceTarget(references, "shop", "users", "userId")
+	assert.False(t, ok)
+
+	_, _, ok = ResolveReferenceTarget(nil, "shop", "orders", "userId")
+	assert.False(t, ok)
+}
@@ -0,0 +1,218 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxTopValues is the number of the most frequent values reported for a field
+	maxTopValues = 5
+)
+
+// Schema describes fields observed in sampled documents of a collection
+type Schema struct {
+	SampleSize int
+	Fields     []FieldSchema
+}
+
+// FieldSchema describes a single field identified by its dotted path.
+// Fields of objects nested in arrays are reported under path of the array
+// (tags.name), their values are counted for every element
+type FieldSchema struct {
+	Path string
+	// Count is the number of documents that have the field
+	Count int
+	// Presence is the ratio of documents that have the field
+	Presence float64
+	Types    []TypeStats
+	// Min and Max are set for numbers and dates, if both are present
+	// in the field, numbers are reported
+	Min, Max  interface{}
+	TopValues []ValueStats
+}
+
+// TypeStats is the number of values of the type and its share in all values of the field
+type TypeStats struct {
+	Type    string
+	Count   int
+	Percent float64
+}
+
+// ValueStats is the number of occurrences of the scalar value
+type ValueStats struct {
+	Value string
+	Count int
+}
+
+type fieldStats struct {
+	count                int
+	values               int
+	types                map[string]int
+	valueFreq            map[string]int
+	minNumber, maxNumber *float64
+	minDate, maxDate     *primitive.DateTime
+}
+
+// SampleDocuments returns up to size random documents of the collection
+func (d *Dao) SampleDocuments(ctx context.Context, db string, collection string, size int) ([]primitive.M, error) {
+	pipeline := primitive.A{primitive.M{"$sample": primitive.M{"size": size}}}
+	cursor, err := d.client.Database(db).Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	documents := []primitive.M{}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// AnalyzeSchema reports types, presence, ranges and frequent values of all
+// fields in the documents, fields are sorted by their path
+func AnalyzeSchema(documents []primitive.M) *Schema {
+	stats := map[string]*fieldStats{}
+	for _, doc := range documents {
+		values := map[string][]interface{}{}
+		collectValues(values, "", doc)
+		for path, fieldValues := range values {
+			s, ok := stats[path]
+			if !ok {
+				s = &fieldStats{types: map[string]int{}, valueFreq: map[string]int{}}
+				stats[path] = s
+			}
+			s.count++
+			for _, value := range fieldValues {
+				s.add(value)
+			}
+		}
+	}
+
+	schema := &Schema{SampleSize: len(documents), Fields: make([]FieldSchema, 0, len(stats))}
+	for path, s := range stats {
+		schema.Fields = append(schema.Fields, s.toFieldSchema(path, len(documents)))
+	}
+	sort.Slice(schema.Fields, func(i, j int) bool {
+		return schema.Fields[i].Path < schema.Fields[j].Path
+	})
+
+	return schema
+}
+
+// collectValues gathers values of all fields of the object, including nested ones
+func collectValues(values map[string][]interface{}, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case primitive.M:
+		collectObjectValues(values, prefix, v)
+	case map[string]interface{}:
+		collectObjectValues(values, prefix, v)
+	case primitive.D:
+		collectObjectValues(values, prefix, v.Map())
+	case primitive.A:
+		for _, elem := range v {
+			switch elem.(type) {
+			case primitive.M, map[string]interface{}, primitive.D:
+				collectValues(values, prefix, elem)
+			}
+		}
+	}
+}
+
+func collectObjectValues(values map[string][]interface{}, prefix string, obj map[string]interface{}) {
+	for key, value := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		values[path] = append(values[path], value)
+		collectValues(values, path, value)
+	}
+}
+
+func (s *fieldStats) add(value interface{}) {
+	s.values++
+	mongoType := util.GetMongoType(value)
+	s.types[mongoType]++
+
+	switch v := value.(type) {
+	case int32:
+		s.addNumber(float64(v))
+	case int64:
+		s.addNumber(float64(v))
+	case int:
+		s.addNumber(float64(v))
+	case float64:
+		s.addNumber(v)
+	case primitive.DateTime:
+		if s.minDate == nil || v < *s.minDate {
+			s.minDate = &v
+		}
+		if s.maxDate == nil || v > *s.maxDate {
+			s.maxDate = &v
+		}
+	}
+
+	switch mongoType {
+	case util.TypeArray, util.TypeObject:
+	default:
+		s.valueFreq[util.GetValueByType(value)]++
+	}
+}
+
+func (s *fieldStats) addNumber(number float64) {
+	if s.minNumber == nil || number < *s.minNumber {
+		s.minNumber = &number
+	}
+	if s.maxNumber == nil || number > *s.maxNumber {
+		s.maxNumber = &number
+	}
+}
+
+func (s *fieldStats) toFieldSchema(path string, total int) FieldSchema {
+	field := FieldSchema{
+		Path:     path,
+		Count:    s.count,
+		Presence: float64(s.count) / float64(total),
+	}
+
+	for mongoType, count := range s.types {
+		field.Types = append(field.Types, TypeStats{
+			Type:    mongoType,
+			Count:   count,
+			Percent: float64(count) / float64(s.values) * 100,
+		})
+	}
+	sort.Slice(field.Types, func(i, j int) bool {
+		if field.Types[i].Count != field.Types[j].Count {
+			return field.Types[i].Count > field.Types[j].Count
+		}
+		return field.Types[i].Type < field.Types[j].Type
+	})
+
+	switch {
+	case s.minNumber != nil:
+		field.Min, field.Max = *s.minNumber, *s.maxNumber
+	case s.minDate != nil:
+		field.Min, field.Max = *s.minDate, *s.maxDate
+	}
+
+	for value, count := range s.valueFreq {
+		field.TopValues = append(field.TopValues, ValueStats{Value: value, Count: count})
+	}
+	sort.Slice(field.TopValues, func(i, j int) bool {
+		if field.TopValues[i].Count != field.TopValues[j].Count {
+			return field.TopValues[i].Count > field.TopValues[j].Count
+		}
+		return field.TopValues[i].Value < field.TopValues[j].Value
+	})
+	if len(field.TopValues) > maxTopValues {
+		field.TopValues = field.TopValues[:maxTopValues]
+	}
+
+	return field
+}
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAnalyzeSchema(t *testing.T) {
+	early := primitive.NewDateTimeFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	late := primitive.NewDateTimeFromTime(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	documents := []primitive.M{
+		{"name": "John", "age": int32(30), "created": early, "address": primitive.M{"city": "Paris"}},
+		{"name": "Jane", "age": int64(45), "created": late, "tags": primitive.A{primitive.M{"label": "a"}, primitive.M{"label": "b"}}},
+		{"name": "John", "age": "unknown", "address": primitive.M{"city": "Paris"}},
+		{"name": nil},
+	}
+
+	schema := AnalyzeSchema(documents)
+	assert.Equal(t, 4, schema.SampleSize)
+
+	paths := []string{}
+	fields := map[string]FieldSchema{}
+	for _, field := range schema.Fields {
+		paths = append(paths, field.Path)
+		fields[field.Path] = field
+	}
+	assert.Equal(t, []string{"address", "address.city", "age", "created", "name", "tags", "tags.label"}, paths)
+
+	name := fields["name"]
+	assert.Equal(t, 4, name.Count)
+	assert.Equal(t, 1.0, name.Presence)
+	assert.Equal(t, []TypeStats{{Type: util.TypeString, Count: 3, Percent: 75}, {Type: util.TypeNull, Count: 1, Percent: 25}}, name.Types)
+	assert.Equal(t, ValueStats{Value: "John", Count: 2}, name.TopValues[0])
+	assert.Nil(t, name.Min)
+
+	age := fields["age"]
+	assert.Equal(t, 0.75, age.Presence)
+	assert.Len(t, age.Types, 3)
+	assert.Equal(t, 30.0, age.Min)
+	assert.Equal(t, 45.0, age.Max)
+
+	created := fields["created"]
+	assert.Equal(t, 0.5, created.Presence)
+	assert.Equal(t, early, created.Min)
+	assert.Equal(t, late, created.Max)
+
+	city := fields["address.city"]
+	assert.Equal(t, 2, city.Count)
+	assert.Equal(t, []ValueStats{{Value: "Paris", Count: 2}}, city.TopValues)
+
+	// objects and arrays are not counted as values
+	assert.Empty(t, fields["address"].TopValues)
+
+	// values of objects in arrays are counted for each element,
+	// but presence is counted once for the document
+	label := fields["tags.label"]
+	assert.Equal(t, 1, label.Count)
+	assert.Equal(t, []TypeStats{{Type: util.TypeString, Count: 2, Percent: 100}}, label.Types)
+}
+
+func TestAnalyzeSchemaTopValuesLimit(t *testing.T) {
+	documents := []primitive.M{}
+	for i := 0; i < 10; i++ {
+		documents = append(documents, primitive.M{"n": int32(i % 7)})
+	}
+
+	schema := AnalyzeSchema(documents)
+	assert.Len(t, schema.Fields, 1)
+	topValues := schema.Fields[0].TopValues
+	assert.Len(t, topValues, maxTopValues)
+	assert.Equal(t, ValueStats{Value: "0", Count: 2}, topValues[0])
+}
+
+func TestAnalyzeSchemaEmpty(t *testing.T) {
+	schema := AnalyzeSchema(nil)
+	assert.Equal(t, 0, schema.SampleSize)
+	assert.Empty(t, schema.Fields)
+}
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CollectionStats are the most important values returned by collStats command
+type CollectionStats struct {
+	Count          int64
+	AvgObjSize     int64
+	Size           int64
+	StorageSize    int64
+	Indexes        int64
+	TotalIndexSize int64
+	Capped         bool
+	TimeSeries     bool
+	// TTLIndexes are descriptions of indexes that expire documents
+	TTLIndexes []string
+}
+
+// DatabaseStats are the most important values returned by dbStats command
+type DatabaseStats struct {
+	Collections int64
+	Views       int64
+	Objects     int64
+	AvgObjSize  int64
+	DataSize    int64
+	StorageSize int64
+	Indexes     int64
+	IndexSize   int64
+}
+
+// GetCollectionStats returns statistics of the collection with its TTL indexes
+func (d *Dao) GetCollectionStats(ctx context.Context, db string, collection string) (*CollectionStats, error) {
+	var result primitive.M
+	err := d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "collStats", Value: collection}}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	stats := parseCollectionStats(result)
+
+	specs, err := d.client.Database(db).ListCollectionSpecifications(ctx, primitive.M{"name": collection})
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		stats.TimeSeries = spec.Type == "timeseries"
+	}
+
+	indexes, err := d.client.Database(db).Collection(collection).Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if index.ExpireAfterSeconds != nil {
+			stats.TTLIndexes = append(stats.TTLIndexes, fmt.Sprintf("%s (%ds)", index.Name, *index.ExpireAfterSeconds))
+		}
+	}
+
+	return stats, nil
+}
+
+// GetDatabaseStats returns statistics of the database
+func (d *Dao) GetDatabaseStats(ctx context.Context, db string) (*DatabaseStats, error) {
+	var result primitive.M
+	err := d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "dbStats", Value: 1}}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return parseDatabaseStats(result), nil
+}
+
+// CountDocuments returns estimated number of documents in the collection
+func (d *Dao) CountDocuments(ctx context.Context, db string, collection string) (int64, error) {
+	return d.client.Database(db).Collection(collection).EstimatedDocumentCount(ctx)
+}
+
+func parseCollectionStats(result primitive.M) *CollectionStats {
+	capped, _ := result["capped"].(bool)
+	return &CollectionStats{
+		Count:          toInt64(result["count"]),
+		AvgObjSize:     toInt64(result["avgObjSize"]),
+		Size:           toInt64(result["size"]),
+		StorageSize:    toInt64(result["storageSize"]),
+		Indexes:        toInt64(result["nindexes"]),
+		TotalIndexSize: toInt64(result["totalIndexSize"]),
+		Capped:         capped,
+	}
+}
+
+func parseDatabaseStats(result primitive.M) *DatabaseStats {
+	return &DatabaseStats{
+		Collections: toInt64(result["collections"]),
+		Views:       toInt64(result["views"]),
+		Objects:     toInt64(result["objects"]),
+		AvgObjSize:  toInt64(result["avgObjSize"]),
+		DataSize:    toInt64(result["dataSize"]),
+		StorageSize: toInt64(result["storageSize"]),
+		Indexes:     toInt64(result["indexes"]),
+		IndexSize:   toInt64(result["indexSize"]),
+	}
+}
+
+// toInt64 converts numbers returned by commands, which type
+// depends on their size, 0 is returned for other values
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseCollectionStats(t *testing.T) {
+	result := primitive.M{
+		"count":          int32(10),
+		"avgObjSize":     float64(120.5),
+		"size":           int64(1205),
+		"storageSize":    int32(4096),
+		"nindexes":       int32(2),
+		"totalIndexSize": int32(8192),
+		"capped":         true,
+	}
+
+	assert.Equal(t, &CollectionStats{
+		Count:          10,
+		AvgObjSize:     120,
+		Size:           1205,
+		StorageSize:    4096,
+		Indexes:        2,
+		TotalIndexSize: 8192,
+		Capped:         true,
+	}, parseCollectionStats(result))
+}
+
+func TestParseDatabaseStats(t *testing.T) {
+	result := primitive.M{
+		"collections": int32(3),
+		"views":       int32(1),
+		"objects":     int64(100),
+		"avgObjSize":  float64(50),
+		"dataSize":    float64(5000),
+		"storageSize": float64(8192),
+		"indexes":     int32(4),
+		"indexSize":   float64(16384),
+	}
+
+	assert.Equal(t, &DatabaseStats{
+		Collections: 3,
+		Views:       1,
+		Objects:     100,
+		AvgObjSize:  50,
+		DataSize:    5000,
+		StorageSize: 8192,
+		Indexes:     4,
+		IndexSize:   16384,
+	}, parseDatabaseStats(result))
+}
+
+func TestToInt64(t *testing.T) {
+	assert.Equal(t, int64(1), toInt64(int32(1)))
+	assert.Equal(t, int64(2), toInt64(int64(2)))
+	assert.Equal(t, int64(3), toInt64(3.7))
+	assert.Equal(t, int64(0), toInt64("4"))
+	assert.Equal(t, int64(0), toInt64(nil))
+}
@@ -0,0 +1,111 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// noReplicationEnabledCode is returned by replSetGetStatus on standalone servers
+	noReplicationEnabledCode = 76
+)
+
+// StatusRates are per second rates of server counters between two statuses
+type StatusRates struct {
+	Insert   float64
+	Query    float64
+	Update   float64
+	Delete   float64
+	GetMore  float64
+	Command  float64
+	BytesIn  float64
+	BytesOut float64
+}
+
+// ReplicationMember is a member of the replica set with its lag behind the primary
+type ReplicationMember struct {
+	Name       string
+	State      string
+	Healthy    bool
+	Optime     time.Time
+	Lag        time.Duration
+	SyncSource string
+}
+
+// ComputeRates computes rates of counters between the previous and the current
+// status, counters that were reset (e.g. by restart) have zero rate
+func ComputeRates(prev, curr *ServerStatus) StatusRates {
+	elapsed := curr.LocalTime.Sub(prev.LocalTime).Seconds()
+	if elapsed <= 0 {
+		return StatusRates{}
+	}
+	rate := func(prev, curr int64) float64 {
+		if curr < prev {
+			return 0
+		}
+		return float64(curr-prev) / elapsed
+	}
+
+	return StatusRates{
+		Insert:   rate(prev.OpCounters.Insert, curr.OpCounters.Insert),
+		Query:    rate(prev.OpCounters.Query, curr.OpCounters.Query),
+		Update:   rate(prev.OpCounters.Update, curr.OpCounters.Update),
+		Delete:   rate(prev.OpCounters.Delete, curr.OpCounters.Delete),
+		GetMore:  rate(prev.OpCounters.GetMore, curr.OpCounters.GetMore),
+		Command:  rate(prev.OpCounters.Command, curr.OpCounters.Command),
+		BytesIn:  rate(prev.Network.BytesIn, curr.Network.BytesIn),
+		BytesOut: rate(prev.Network.BytesOut, curr.Network.BytesOut),
+	}
+}
+
+// GetReplicationMembers returns members of the replica set with their
+// replication lag, nil is returned if the server is not a replica set member
+func (d *Dao) GetReplicationMembers(ctx context.Context) ([]ReplicationMember, error) {
+	status, err := d.runAdminCommand(ctx, "replSetGetStatus", 1)
+	if err != nil {
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == noReplicationEnabledCode {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseReplicationMembers(status), nil
+}
+
+// parseReplicationMembers reads members from replSetGetStatus result,
+// lag is computed from the optime of the primary
+func parseReplicationMembers(status primitive.M) []ReplicationMember {
+	members, _ := status["members"].(primitive.A)
+
+	var primaryOptime primitive.DateTime
+	for _, m := range members {
+		member, _ := m.(primitive.M)
+		if member["stateStr"] == "PRIMARY" {
+			primaryOptime, _ = member["optimeDate"].(primitive.DateTime)
+		}
+	}
+
+	result := []ReplicationMember{}
+	for _, m := range members {
+		member, ok := m.(primitive.M)
+		if !ok {
+			continue
+		}
+		replicationMember := ReplicationMember{Healthy: toInt64(member["health"]) == 1}
+		replicationMember.Name, _ = member["name"].(string)
+		replicationMember.State, _ = member["stateStr"].(string)
+		replicationMember.SyncSource, _ = member["syncSourceHost"].(string)
+		if optime, ok := member["optimeDate"].(primitive.DateTime); ok {
+			replicationMember.Optime = optime.Time()
+			if primaryOptime != 0 && optime < primaryOptime {
+				replicationMember.Lag = primaryOptime.Time().Sub(optime.Time())
+			}
+		}
+		result = append(result, replicationMember)
+	}
+	return result
+}
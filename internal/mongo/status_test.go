@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestComputeRates(t *testing.T) {
+	now := time.Now()
+	prev := &ServerStatus{LocalTime: now}
+	prev.OpCounters = OpCounters{Insert: 100, Query: 50, Update: 10, Delete: 0, GetMore: 5, Command: 1000}
+	prev.Network.BytesIn = 1000
+	prev.Network.BytesOut = 2000
+
+	curr := &ServerStatus{LocalTime: now.Add(2 * time.Second)}
+	curr.OpCounters = OpCounters{Insert: 120, Query: 50, Update: 14, Delete: 0, GetMore: 1, Command: 1010}
+	curr.Network.BytesIn = 3000
+	curr.Network.BytesOut = 2000
+
+	assert.Equal(t, StatusRates{
+		Insert:   10,
+		Query:    0,
+		Update:   2,
+		Delete:   0,
+		GetMore:  0,
+		Command:  5,
+		BytesIn:  1000,
+		BytesOut: 0,
+	}, ComputeRates(prev, curr))
+}
+
+func TestComputeRatesWithoutElapsedTime(t *testing.T) {
+	now := time.Now()
+	prev := &ServerStatus{LocalTime: now}
+	curr := &ServerStatus{LocalTime: now}
+	curr.OpCounters.Insert = 10
+
+	assert.Equal(t, StatusRates{}, ComputeRates(prev, curr))
+}
+
+func TestParseReplicationMembers(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	status := primitive.M{
+		"members": primitive.A{
+			primitive.M{"name": "a:27017", "stateStr": "PRIMARY", "health": 1.0, "optimeDate": primitive.NewDateTimeFromTime(now)},
+			primitive.M{"name": "b:27017", "stateStr": "SECONDARY", "health": 1.0, "syncSourceHost": "a:27017",
+				"optimeDate": primitive.NewDateTimeFromTime(now.Add(-3 * time.Second))},
+			primitive.M{"name": "c:27017", "stateStr": "(not reachable/healthy)", "health": 0.0},
+		},
+	}
+
+	assert.Equal(t, []ReplicationMember{
+		{Name: "a:27017", State: "PRIMARY", Healthy: true, Optime: now},
+		{Name: "b:27017", State: "SECONDARY", Healthy: true, Optime: now.Add(-3 * time.Second), Lag: 3 * time.Second, SyncSource: "a:27017"},
+		{Name: "c:27017", State: "(not reachable/healthy)"},
+	}, parseReplicationMembers(status))
+}
@@ -0,0 +1,152 @@
+package mongo
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Shard is a shard of the cluster returned by listShards
+type Shard struct {
+	ID       string
+	Host     string
+	Draining bool
+}
+
+// ShardedCollection is a sharded collection with number of chunks per shard
+type ShardedCollection struct {
+	Namespace string
+	Chunks    map[string]int64
+}
+
+// BalancerStatus is the state of the cluster balancer
+type BalancerStatus struct {
+	Mode    string
+	InRound bool
+}
+
+// ShardingStatus is the topology of a sharded cluster
+type ShardingStatus struct {
+	Shards      []Shard
+	Collections []ShardedCollection
+	Balancer    BalancerStatus
+}
+
+// IsMongos checks if the client is connected to a mongos router
+func (d *Dao) IsMongos(ctx context.Context) (bool, error) {
+	hello, err := d.runAdminCommand(ctx, "hello", 1)
+	if err != nil {
+		return false, err
+	}
+	return hello["msg"] == "isdbgrid", nil
+}
+
+// GetShardingStatus returns shards, chunk distribution of sharded
+// collections and balancer state, it works only on mongos
+func (d *Dao) GetShardingStatus(ctx context.Context) (*ShardingStatus, error) {
+	listShards, err := d.runAdminCommand(ctx, "listShards", 1)
+	if err != nil {
+		return nil, err
+	}
+	balancer, err := d.runAdminCommand(ctx, "balancerStatus", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	config := d.client.Database("config")
+	cursor, err := config.Collection("collections").Find(ctx, primitive.M{"dropped": primitive.M{"$ne": true}})
+	if err != nil {
+		return nil, err
+	}
+	var collections []primitive.M
+	if err := cursor.All(ctx, &collections); err != nil {
+		return nil, err
+	}
+
+	// chunks reference collections by uuid since 5.0 and by namespace before
+	pipeline := primitive.A{
+		primitive.M{"$group": primitive.M{
+			"_id":   primitive.M{"uuid": "$uuid", "ns": "$ns", "shard": "$shard"},
+			"count": primitive.M{"$sum": 1},
+		}},
+	}
+	cursor, err = config.Collection("chunks").Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	var chunkCounts []primitive.M
+	if err := cursor.All(ctx, &chunkCounts); err != nil {
+		return nil, err
+	}
+
+	mode, _ := balancer["mode"].(string)
+	inRound, _ := balancer["inBalancerRound"].(bool)
+
+	return &ShardingStatus{
+		Shards:      parseShards(listShards),
+		Collections: parseChunkDistribution(collections, chunkCounts),
+		Balancer:    BalancerStatus{Mode: mode, InRound: inRound},
+	}, nil
+}
+
+func parseShards(listShards primitive.M) []Shard {
+	shards, _ := listShards["shards"].(primitive.A)
+
+	result := []Shard{}
+	for _, s := range shards {
+		shard, ok := s.(primitive.M)
+		if !ok {
+			continue
+		}
+		parsed := Shard{}
+		parsed.ID, _ = shard["_id"].(string)
+		parsed.Host, _ = shard["host"].(string)
+		parsed.Draining, _ = shard["draining"].(bool)
+		result = append(result, parsed)
+	}
+	return result
+}
+
+// parseChunkDistribution matches chunk counts grouped by collection
+// and shard with collections from config.collections
+func parseChunkDistribution(collections []primitive.M, chunkCounts []primitive.M) []ShardedCollection {
+	byUUID := map[string]*ShardedCollection{}
+	byNamespace := map[string]*ShardedCollection{}
+	result := make([]*ShardedCollection, 0, len(collections))
+	for _, coll := range collections {
+		namespace, _ := coll["_id"].(string)
+		sharded := &ShardedCollection{Namespace: namespace, Chunks: map[string]int64{}}
+		byNamespace[namespace] = sharded
+		if uuid, ok := coll["uuid"].(primitive.Binary); ok {
+			byUUID[hex.EncodeToString(uuid.Data)] = sharded
+		}
+		result = append(result, sharded)
+	}
+
+	for _, count := range chunkCounts {
+		id, _ := count["_id"].(primitive.M)
+		shard, _ := id["shard"].(string)
+
+		var sharded *ShardedCollection
+		if uuid, ok := id["uuid"].(primitive.Binary); ok {
+			sharded = byUUID[hex.EncodeToString(uuid.Data)]
+		} else if namespace, ok := id["ns"].(string); ok {
+			sharded = byNamespace[namespace]
+		}
+		if sharded != nil {
+			sharded.Chunks[shard] += toInt64(count["count"])
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Namespace < result[j].Namespace
+	})
+	distribution := make([]ShardedCollection, 0, len(result))
+	for _, sharded := range result {
+		distribution = append(distribution, *sharded)
+	}
+	return distribution
+}
@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseShards(t *testing.T) {
+	listShards := primitive.M{
+		"shards": primitive.A{
+			primitive.M{"_id": "rs0", "host": "rs0/a:27017,b:27017", "state": int32(1)},
+			primitive.M{"_id": "rs1", "host": "rs1/c:27017", "state": int32(1), "draining": true},
+		},
+	}
+
+	assert.Equal(t, []Shard{
+		{ID: "rs0", Host: "rs0/a:27017,b:27017"},
+		{ID: "rs1", Host: "rs1/c:27017", Draining: true},
+	}, parseShards(listShards))
+}
+
+func TestParseChunkDistribution(t *testing.T) {
+	ordersUUID := primitive.Binary{Subtype: 4, Data: []byte{1, 2, 3}}
+	collections := []primitive.M{
+		{"_id": "shop.users"},
+		{"_id": "shop.orders", "uuid": ordersUUID},
+	}
+	chunkCounts := []primitive.M{
+		{"_id": primitive.M{"uuid": ordersUUID, "shard": "rs0"}, "count": int32(3)},
+		{"_id": primitive.M{"uuid": ordersUUID, "shard": "rs1"}, "count": int32(2)},
+		{"_id": primitive.M{"ns": "shop.users", "shard": "rs0"}, "count": int32(1)},
+		{"_id": primitive.M{"ns": "shop.dropped", "shard": "rs0"}, "count": int32(7)},
+	}
+
+	assert.Equal(t, []ShardedCollection{
+		{Namespace: "shop.orders", Chunks: map[string]int64{"rs0": 3, "rs1": 2}},
+		{Namespace: "shop.users", Chunks: map[string]int64{"rs0": 1}},
+	}, parseChunkDistribution(collections, chunkCounts))
+}
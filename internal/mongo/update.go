@@ -0,0 +1,223 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BuildUpdate compares the original document with the updated one and returns
+// update operations ($set and $unset) needed to transform the first into the second.
+// If there is nothing to update, an empty map is returned.
+func BuildUpdate(originalDoc, document primitive.M) primitive.M {
+	setOps := primitive.M{}
+	unsetOps := primitive.M{}
+
+	for key, value := range document {
+		if origValue, exists := originalDoc[key]; !exists || !reflect.DeepEqual(origValue, value) {
+			setOps[key] = value
+		}
+	}
+
+	for key := range originalDoc {
+		if _, exists := document[key]; !exists {
+			unsetOps[key] = 1
+		}
+	}
+
+	update := primitive.M{}
+	if len(setOps) > 0 {
+		update["$set"] = setOps
+	}
+	if len(unsetOps) > 0 {
+		update["$unset"] = unsetOps
+	}
+
+	return update
+}
+
+// BuildVersionedUpdate builds the update like BuildUpdate, but if versionField
+// is set and present in the original document, the version is never taken
+// from the edited document, it's always incremented instead. It prevents
+// writing back a stale version, e.g. when a conflicting document is overwritten
+func BuildVersionedUpdate(originalDoc, document primitive.M, versionField string) primitive.M {
+	update := BuildUpdate(originalDoc, document)
+	if versionField == "" {
+		return update
+	}
+	if _, ok := originalDoc[versionField]; !ok {
+		return update
+	}
+
+	for _, operator := range []string{"$set", "$unset"} {
+		if fields, ok := update[operator].(primitive.M); ok {
+			delete(fields, versionField)
+			if len(fields) == 0 {
+				delete(update, operator)
+			}
+		}
+	}
+	if len(update) > 0 {
+		update["$inc"] = primitive.M{versionField: 1}
+	}
+
+	return update
+}
+
+// revertUpdate returns the document as it was before the update built by
+// BuildVersionedUpdate. The concurrency filter ensures that all updated fields
+// had values of originalDoc, so the updated document doesn't have to be read
+// before the update, which would not be atomic with it
+func revertUpdate(updated, originalDoc, update primitive.M) primitive.M {
+	before := primitive.M{}
+	for key, value := range updated {
+		before[key] = value
+	}
+	for _, operator := range []string{"$set", "$unset", "$inc"} {
+		fields, ok := update[operator].(primitive.M)
+		if !ok {
+			continue
+		}
+		for key := range fields {
+			if value, exists := originalDoc[key]; exists {
+				before[key] = value
+			} else {
+				delete(before, key)
+			}
+		}
+	}
+	return before
+}
+
+// BuildConcurrencyFilter returns a filter that matches the document of given _id
+// only if it was not changed since originalDoc was read. If versionField is set and
+// present in the original document, only the version is compared, otherwise
+// the original values of fields touched by the update are used.
+func BuildConcurrencyFilter(id interface{}, originalDoc, update primitive.M, versionField string) primitive.M {
+	filter := primitive.M{"_id": id}
+
+	if versionField != "" {
+		if version, ok := originalDoc[versionField]; ok {
+			filter[versionField] = version
+			return filter
+		}
+	}
+
+	for _, operator := range []string{"$set", "$unset"} {
+		fields, ok := update[operator].(primitive.M)
+		if !ok {
+			continue
+		}
+		for key := range fields {
+			origValue, exists := originalDoc[key]
+			if !exists {
+				filter[key] = primitive.M{"$exists": false}
+				continue
+			}
+			addValueToFilter(filter, key, origValue)
+		}
+	}
+
+	return filter
+}
+
+// addValueToFilter adds conditions matching the value under given path.
+// Embedded documents are compared field by field using dot notation,
+// as the order of keys in decoded documents is not preserved.
+func addValueToFilter(filter primitive.M, path string, value interface{}) {
+	switch v := value.(type) {
+	case primitive.M:
+		addObjectToFilter(filter, path, v)
+	case map[string]interface{}:
+		addObjectToFilter(filter, path, v)
+	case primitive.A:
+		addArrayToFilter(filter, path, v)
+	case []interface{}:
+		addArrayToFilter(filter, path, v)
+	default:
+		filter[path] = value
+	}
+}
+
+func addObjectToFilter(filter primitive.M, path string, object map[string]interface{}) {
+	if len(object) == 0 {
+		filter[path] = primitive.M{}
+		return
+	}
+	for key, value := range object {
+		addValueToFilter(filter, path+"."+key, value)
+	}
+}
+
+func addArrayToFilter(filter primitive.M, path string, array []interface{}) {
+	filter[path] = primitive.M{"$size": len(array)}
+	for i, value := range array {
+		addValueToFilter(filter, fmt.Sprintf("%s.%d", path, i), value)
+	}
+}
+
+// MergeConflict describes a field that was changed differently
+// in both versions of the document
+type MergeConflict struct {
+	Key    string
+	Base   interface{}
+	Mine   interface{}
+	Theirs interface{}
+}
+
+// MergeDocuments performs a three-way merge of top level fields. Changes made
+// only in one of the versions are applied, for fields changed in both versions
+// our value is used and the field is reported as a conflict.
+func MergeDocuments(base, mine, theirs primitive.M) (primitive.M, []MergeConflict) {
+	merged := primitive.M{}
+	conflicts := []MergeConflict{}
+
+	keys := map[string]bool{}
+	for _, doc := range []primitive.M{base, mine, theirs} {
+		for key := range doc {
+			keys[key] = true
+		}
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		baseValue, inBase := base[key]
+		mineValue, inMine := mine[key]
+		theirsValue, inTheirs := theirs[key]
+
+		mineChanged := inBase != inMine || !reflect.DeepEqual(baseValue, mineValue)
+		theirsChanged := inBase != inTheirs || !reflect.DeepEqual(baseValue, theirsValue)
+
+		switch {
+		case !mineChanged:
+			if inTheirs {
+				merged[key] = theirsValue
+			}
+		case !theirsChanged:
+			if inMine {
+				merged[key] = mineValue
+			}
+		default:
+			if inMine {
+				merged[key] = mineValue
+			}
+			if inMine != inTheirs || !reflect.DeepEqual(mineValue, theirsValue) {
+				conflicts = append(conflicts, MergeConflict{
+					Key:    key,
+					Base:   baseValue,
+					Mine:   mineValue,
+					Theirs: theirsValue,
+				})
+			}
+		}
+	}
+
+	return merged, conflicts
+}
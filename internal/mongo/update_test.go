@@ -0,0 +1,205 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildUpdate(t *testing.T) {
+	cases := []struct {
+		name     string
+		original primitive.M
+		updated  primitive.M
+		expected primitive.M
+	}{
+		{
+			name:     "No changes",
+			original: primitive.M{"name": "John", "age": 30},
+			updated:  primitive.M{"name": "John", "age": 30},
+			expected: primitive.M{},
+		},
+		{
+			name:     "Changed and added fields",
+			original: primitive.M{"name": "John", "age": 30},
+			updated:  primitive.M{"name": "Jane", "age": 30, "email": "jane@example.com"},
+			expected: primitive.M{
+				"$set": primitive.M{"name": "Jane", "email": "jane@example.com"},
+			},
+		},
+		{
+			name:     "Removed field",
+			original: primitive.M{"name": "John", "age": 30},
+			updated:  primitive.M{"name": "John"},
+			expected: primitive.M{
+				"$unset": primitive.M{"age": 1},
+			},
+		},
+		{
+			name:     "Nested change",
+			original: primitive.M{"address": map[string]interface{}{"city": "Berlin"}, "tags": []interface{}{"a"}},
+			updated:  primitive.M{"address": map[string]interface{}{"city": "Paris"}},
+			expected: primitive.M{
+				"$set":   primitive.M{"address": map[string]interface{}{"city": "Paris"}},
+				"$unset": primitive.M{"tags": 1},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BuildUpdate(tc.original, tc.updated))
+		})
+	}
+}
+
+func TestBuildVersionedUpdate(t *testing.T) {
+	t.Run("Version is incremented", func(t *testing.T) {
+		original := primitive.M{"name": "John", "v": int64(3)}
+		edited := primitive.M{"name": "Jane", "v": int64(3)}
+		assert.Equal(t, primitive.M{
+			"$set": primitive.M{"name": "Jane"},
+			"$inc": primitive.M{"v": 1},
+		}, BuildVersionedUpdate(original, edited, "v"))
+	})
+
+	t.Run("Overwrite with stale version", func(t *testing.T) {
+		// current document has version 4, our edit was based on version 3
+		current := primitive.M{"name": "Jim", "v": int64(4)}
+		edited := primitive.M{"name": "Jane", "v": int64(3)}
+		update := BuildVersionedUpdate(current, edited, "v")
+		assert.Equal(t, primitive.M{
+			"$set": primitive.M{"name": "Jane"},
+			"$inc": primitive.M{"v": 1},
+		}, update)
+		assert.Equal(t, primitive.M{"_id": "1", "v": int64(4)}, BuildConcurrencyFilter("1", current, update, "v"))
+	})
+
+	t.Run("Removed version is not unset", func(t *testing.T) {
+		original := primitive.M{"name": "John", "v": int64(3)}
+		edited := primitive.M{"name": "John"}
+		assert.Empty(t, BuildVersionedUpdate(original, edited, "v"))
+	})
+
+	t.Run("Single field update", func(t *testing.T) {
+		// inline edit passes only the edited field and the version
+		original := primitive.M{"address.city": "Warsaw", "v": int64(3)}
+		update := BuildVersionedUpdate(original, primitive.M{"address.city": "Krakow"}, "v")
+		assert.Equal(t, primitive.M{
+			"$set": primitive.M{"address.city": "Krakow"},
+			"$inc": primitive.M{"v": 1},
+		}, update)
+		assert.Equal(t, primitive.M{"_id": "1", "v": int64(3)}, BuildConcurrencyFilter("1", original, update, "v"))
+	})
+
+	t.Run("Document without version", func(t *testing.T) {
+		original := primitive.M{"name": "John"}
+		edited := primitive.M{"name": "Jane"}
+		assert.Equal(t, primitive.M{"$set": primitive.M{"name": "Jane"}}, BuildVersionedUpdate(original, edited, "v"))
+	})
+}
+
+func TestRevertUpdate(t *testing.T) {
+	originalDoc := primitive.M{"_id": 1, "name": "John", "age": 30, "city": "Warsaw", "v": 4}
+	document := primitive.M{"_id": 1, "name": "Jane", "email": "jane@example.com", "city": "Warsaw", "v": 4}
+	update := BuildVersionedUpdate(originalDoc, document, "v")
+
+	// fields not touched by the update are taken from the updated document
+	updated := primitive.M{"_id": 1, "name": "Jane", "email": "jane@example.com", "city": "Berlin", "v": 5}
+
+	assert.Equal(t, primitive.M{"_id": 1, "name": "John", "age": 30, "city": "Berlin", "v": 4},
+		revertUpdate(updated, originalDoc, update))
+}
+
+func TestBuildConcurrencyFilter(t *testing.T) {
+	cases := []struct {
+		name         string
+		original     primitive.M
+		update       primitive.M
+		versionField string
+		expected     primitive.M
+	}{
+		{
+			name:     "Modified and unset fields",
+			original: primitive.M{"name": "John", "age": 30, "city": "Berlin"},
+			update: primitive.M{
+				"$set":   primitive.M{"name": "Jane", "email": "jane@example.com"},
+				"$unset": primitive.M{"city": 1},
+			},
+			expected: primitive.M{
+				"_id":   "1",
+				"name":  "John",
+				"email": primitive.M{"$exists": false},
+				"city":  "Berlin",
+			},
+		},
+		{
+			name: "Nested values",
+			original: primitive.M{
+				"address": map[string]interface{}{"city": "Berlin", "zip": "10115"},
+				"tags":    []interface{}{"a", map[string]interface{}{"b": 1}},
+			},
+			update: primitive.M{
+				"$set": primitive.M{"address": map[string]interface{}{"city": "Paris"}, "tags": []interface{}{}},
+			},
+			expected: primitive.M{
+				"_id":          "1",
+				"address.city": "Berlin",
+				"address.zip":  "10115",
+				"tags":         primitive.M{"$size": 2},
+				"tags.0":       "a",
+				"tags.1.b":     1,
+			},
+		},
+		{
+			name:         "Version field",
+			original:     primitive.M{"name": "John", "version": 3},
+			update:       primitive.M{"$set": primitive.M{"name": "Jane"}},
+			versionField: "version",
+			expected:     primitive.M{"_id": "1", "version": 3},
+		},
+		{
+			name:         "Missing version field falls back to modified fields",
+			original:     primitive.M{"name": "John"},
+			update:       primitive.M{"$set": primitive.M{"name": "Jane"}},
+			versionField: "version",
+			expected:     primitive.M{"_id": "1", "name": "John"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := BuildConcurrencyFilter("1", tc.original, tc.update, tc.versionField)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestMergeDocuments(t *testing.T) {
+	base := primitive.M{"name": "John", "age": 30, "city": "Berlin", "note": "a"}
+	mine := primitive.M{"name": "Johnny", "age": 30, "city": "Berlin", "email": "john@example.com"}
+	theirs := primitive.M{"name": "John", "age": 31, "note": "b"}
+
+	merged, conflicts := MergeDocuments(base, mine, theirs)
+
+	assert.Equal(t, primitive.M{
+		"name":  "Johnny",
+		"age":   31,
+		"email": "john@example.com",
+	}, merged)
+	assert.Equal(t, []MergeConflict{
+		{Key: "note", Base: "a", Mine: nil, Theirs: "b"},
+	}, conflicts)
+}
+
+func TestMergeDocuments_SameChangeIsNotConflict(t *testing.T) {
+	base := primitive.M{"name": "John"}
+	mine := primitive.M{"name": "Jane"}
+	theirs := primitive.M{"name": "Jane"}
+
+	merged, conflicts := MergeDocuments(base, mine, theirs)
+
+	assert.Equal(t, primitive.M{"name": "Jane"}, merged)
+	assert.Empty(t, conflicts)
+}
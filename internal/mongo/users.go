@@ -0,0 +1,261 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoleRef is a reference to a role defined in a database
+type RoleRef struct {
+	Role string
+	Db   string
+}
+
+// String returns the role in form of role@db
+func (r RoleRef) String() string {
+	return r.Role + "@" + r.Db
+}
+
+// ParseRoleRef parses role in form of role@db, role without
+// database is defined in the defaultDb
+func ParseRoleRef(text, defaultDb string) (RoleRef, error) {
+	text = strings.TrimSpace(text)
+	role, db := text, defaultDb
+	if i := strings.LastIndex(text, "@"); i >= 0 {
+		role, db = text[:i], text[i+1:]
+	}
+	if role == "" || db == "" {
+		return RoleRef{}, fmt.Errorf("role must be in form of role@db, got %q", text)
+	}
+	return RoleRef{Role: role, Db: db}, nil
+}
+
+// DbUser is a user defined in a database
+type DbUser struct {
+	Name  string
+	Db    string
+	Roles []RoleRef
+}
+
+// DbRole is a role available in a database
+type DbRole struct {
+	Name    string
+	Db      string
+	BuiltIn bool
+	// Roles are roles from which this role inherits privileges
+	Roles []RoleRef
+}
+
+// GetUsers returns users defined in the database
+func (d *Dao) GetUsers(ctx context.Context, db string) ([]DbUser, error) {
+	var result primitive.M
+	err := d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "usersInfo", Value: 1}}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return parseUsers(result), nil
+}
+
+// GetRoles returns built-in and user-defined roles of the database
+func (d *Dao) GetRoles(ctx context.Context, db string) ([]DbRole, error) {
+	var result primitive.M
+	command := primitive.D{{Key: "rolesInfo", Value: 1}, {Key: "showBuiltinRoles", Value: true}}
+	err := d.client.Database(db).RunCommand(ctx, command).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return parseRoles(result), nil
+}
+
+// CreateUser creates the user in the database with given roles
+func (d *Dao) CreateUser(ctx context.Context, db, name, password string, roles []RoleRef) error {
+	if name == "" || password == "" {
+		return fmt.Errorf("name and password are required")
+	}
+	command := primitive.D{
+		{Key: "createUser", Value: name},
+		{Key: "pwd", Value: password},
+		{Key: "roles", Value: rolesToCommand(roles)},
+	}
+
+	// password is never logged
+	entry := AuditEntry{
+		Operation: CreateUserOperation,
+		Db:        db,
+		After:     primitive.M{"user": name, "roles": rolesToAudit(roles)},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("User created, db: %v, user: %v, roles: %v", db, name, roles)
+
+	return nil
+}
+
+// GrantRoles grants roles to the user
+func (d *Dao) GrantRoles(ctx context.Context, db, user string, roles []RoleRef) error {
+	command := primitive.D{{Key: "grantRolesToUser", Value: user}, {Key: "roles", Value: rolesToCommand(roles)}}
+	entry := AuditEntry{
+		Operation: GrantRolesOperation,
+		Db:        db,
+		After:     primitive.M{"user": user, "roles": rolesToAudit(roles)},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Roles granted, db: %v, user: %v, roles: %v", db, user, roles)
+
+	return nil
+}
+
+// RevokeRoles revokes roles from the user
+func (d *Dao) RevokeRoles(ctx context.Context, db, user string, roles []RoleRef) error {
+	command := primitive.D{{Key: "revokeRolesFromUser", Value: user}, {Key: "roles", Value: rolesToCommand(roles)}}
+	entry := AuditEntry{
+		Operation: RevokeRolesOperation,
+		Db:        db,
+		Before:    primitive.M{"user": user, "roles": rolesToAudit(roles)},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Roles revoked, db: %v, user: %v, roles: %v", db, user, roles)
+
+	return nil
+}
+
+// ChangeUserPassword changes password of the user
+func (d *Dao) ChangeUserPassword(ctx context.Context, db, user, password string) error {
+	if password == "" {
+		return fmt.Errorf("password cannot be empty")
+	}
+	command := primitive.D{{Key: "updateUser", Value: user}, {Key: "pwd", Value: password}}
+	entry := AuditEntry{
+		Operation: ChangePasswordOperation,
+		Db:        db,
+		After:     primitive.M{"user": user},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Password changed, db: %v, user: %v", db, user)
+
+	return nil
+}
+
+// DropUser removes the user from the database
+func (d *Dao) DropUser(ctx context.Context, db, user string) error {
+	entry := AuditEntry{
+		Operation: DropUserOperation,
+		Db:        db,
+		Before:    primitive.M{"user": user},
+	}
+	err := d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "dropUser", Value: user}}).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("User dropped, db: %v, user: %v", db, user)
+
+	return nil
+}
+
+func rolesToCommand(roles []RoleRef) primitive.A {
+	result := primitive.A{}
+	for _, role := range roles {
+		result = append(result, primitive.M{"role": role.Role, "db": role.Db})
+	}
+	return result
+}
+
+// rolesToAudit returns roles in form of role@db for the audit log
+func rolesToAudit(roles []RoleRef) primitive.A {
+	result := primitive.A{}
+	for _, role := range roles {
+		result = append(result, role.String())
+	}
+	return result
+}
+
+func parseRoleRefs(value interface{}) []RoleRef {
+	roles, _ := value.(primitive.A)
+	result := []RoleRef{}
+	for _, r := range roles {
+		role, ok := r.(primitive.M)
+		if !ok {
+			continue
+		}
+		ref := RoleRef{}
+		ref.Role, _ = role["role"].(string)
+		ref.Db, _ = role["db"].(string)
+		result = append(result, ref)
+	}
+	return result
+}
+
+func parseUsers(result primitive.M) []DbUser {
+	users, _ := result["users"].(primitive.A)
+	parsed := []DbUser{}
+	for _, u := range users {
+		user, ok := u.(primitive.M)
+		if !ok {
+			continue
+		}
+		dbUser := DbUser{Roles: parseRoleRefs(user["roles"])}
+		dbUser.Name, _ = user["user"].(string)
+		dbUser.Db, _ = user["db"].(string)
+		parsed = append(parsed, dbUser)
+	}
+	sort.Slice(parsed, func(i, j int) bool {
+		return parsed[i].Name < parsed[j].Name
+	})
+	return parsed
+}
+
+// parseRoles reads roles from rolesInfo result, user-defined
+// roles are sorted before built-in ones
+func parseRoles(result primitive.M) []DbRole {
+	roles, _ := result["roles"].(primitive.A)
+	parsed := []DbRole{}
+	for _, r := range roles {
+		role, ok := r.(primitive.M)
+		if !ok {
+			continue
+		}
+		dbRole := DbRole{Roles: parseRoleRefs(role["roles"])}
+		dbRole.Name, _ = role["role"].(string)
+		dbRole.Db, _ = role["db"].(string)
+		dbRole.BuiltIn, _ = role["isBuiltin"].(bool)
+		parsed = append(parsed, dbRole)
+	}
+	sort.SliceStable(parsed, func(i, j int) bool {
+		if parsed[i].BuiltIn != parsed[j].BuiltIn {
+			return !parsed[i].BuiltIn
+		}
+		return parsed[i].Name < parsed[j].Name
+	})
+	return parsed
+}
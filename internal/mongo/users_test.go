@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseRoleRef(t *testing.T) {
+	role, err := ParseRoleRef("readWrite@shop", "admin")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleRef{Role: "readWrite", Db: "shop"}, role)
+
+	role, err = ParseRoleRef(" read ", "shop")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleRef{Role: "read", Db: "shop"}, role)
+	assert.Equal(t, "read@shop", role.String())
+
+	_, err = ParseRoleRef("read@", "shop")
+	assert.Error(t, err)
+	_, err = ParseRoleRef("", "shop")
+	assert.Error(t, err)
+}
+
+func TestParseUsers(t *testing.T) {
+	result := primitive.M{
+		"users": primitive.A{
+			primitive.M{"user": "writer", "db": "shop", "roles": primitive.A{
+				primitive.M{"role": "readWrite", "db": "shop"},
+				primitive.M{"role": "read", "db": "reports"},
+			}},
+			primitive.M{"user": "app", "db": "shop", "roles": primitive.A{}},
+		},
+	}
+
+	assert.Equal(t, []DbUser{
+		{Name: "app", Db: "shop", Roles: []RoleRef{}},
+		{Name: "writer", Db: "shop", Roles: []RoleRef{{Role: "readWrite", Db: "shop"}, {Role: "read", Db: "reports"}}},
+	}, parseUsers(result))
+}
+
+func TestParseRoles(t *testing.T) {
+	result := primitive.M{
+		"roles": primitive.A{
+			primitive.M{"role": "read", "db": "shop", "isBuiltin": true, "roles": primitive.A{}},
+			primitive.M{"role": "reporter", "db": "shop", "isBuiltin": false, "roles": primitive.A{
+				primitive.M{"role": "read", "db": "shop"},
+			}},
+			primitive.M{"role": "dbAdmin", "db": "shop", "isBuiltin": true, "roles": primitive.A{}},
+		},
+	}
+
+	assert.Equal(t, []DbRole{
+		{Name: "reporter", Db: "shop", Roles: []RoleRef{{Role: "read", Db: "shop"}}},
+		{Name: "dbAdmin", Db: "shop", BuiltIn: true, Roles: []RoleRef{}},
+		{Name: "read", Db: "shop", BuiltIn: true, Roles: []RoleRef{}},
+	}, parseRoles(result))
+}
+
+func TestRolesToAudit(t *testing.T) {
+	roles := []RoleRef{{Role: "readWrite", Db: "shop"}, {Role: "read", Db: "reporting"}}
+
+	assert.Equal(t, primitive.A{"readWrite@shop", "read@reporting"}, rolesToAudit(roles))
+	assert.Equal(t, primitive.A{}, rolesToAudit(nil))
+}
@@ -0,0 +1,198 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ValidationLevels are allowed values of validationLevel
+	ValidationLevels = []string{"off", "strict", "moderate"}
+	// ValidationActions are allowed values of validationAction
+	ValidationActions = []string{"error", "warn"}
+)
+
+// CollectionValidation are validation rules of a collection,
+// Validator is usually a $jsonSchema document
+type CollectionValidation struct {
+	Validator primitive.D `bson:"validator"`
+	Level     string      `bson:"validationLevel"`
+	Action    string      `bson:"validationAction"`
+}
+
+// ValidationResult is the result of the validate command
+type ValidationResult struct {
+	Valid            bool
+	Records          int64
+	InvalidDocuments int64
+	Warnings         []string
+	Errors           []string
+}
+
+// GetCollectionValidation returns validation rules of the collection
+// from listCollections options, defaults are used for missing values
+func (d *Dao) GetCollectionValidation(ctx context.Context, db string, collection string) (*CollectionValidation, error) {
+	specs, err := d.client.Database(db).ListCollectionSpecifications(ctx, primitive.M{"name": collection})
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("collection %s.%s not found", db, collection)
+	}
+
+	validation := &CollectionValidation{}
+	if specs[0].Options != nil {
+		if err := bson.Unmarshal(specs[0].Options, validation); err != nil {
+			return nil, err
+		}
+	}
+	validation.setDefaults()
+	return validation, nil
+}
+
+// SetCollectionValidation applies validation rules to the collection with collMod
+func (d *Dao) SetCollectionValidation(ctx context.Context, db string, collection string, validation CollectionValidation) error {
+	validation.setDefaults()
+	if err := validation.Validate(); err != nil {
+		return err
+	}
+	before, err := d.GetCollectionValidation(ctx, db, collection)
+	if err != nil {
+		return err
+	}
+
+	command := primitive.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: validation.Validator},
+		{Key: "validationLevel", Value: validation.Level},
+		{Key: "validationAction", Value: validation.Action},
+	}
+	entry := AuditEntry{
+		Operation:  SetValidationOperation,
+		Db:         db,
+		Collection: collection,
+		Before:     before.toAuditImage(),
+		After:      validation.toAuditImage(),
+	}
+	err = d.write(entry, func(*AuditEntry) error {
+		return d.client.Database(db).RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Validation changed, db: %v, collection: %v, level: %v, action: %v", db, collection, validation.Level, validation.Action)
+
+	return nil
+}
+
+// ValidateCollection runs the validate command that checks
+// the collection data and its documents against validation rules
+func (d *Dao) ValidateCollection(ctx context.Context, db string, collection string) (*ValidationResult, error) {
+	var result primitive.M
+	err := d.client.Database(db).RunCommand(ctx, primitive.D{{Key: "validate", Value: collection}}).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return parseValidationResult(result), nil
+}
+
+// CountInvalidDocuments counts all documents of the collection and documents
+// that don't match the validator, it's a dry run of new validation rules
+func (d *Dao) CountInvalidDocuments(ctx context.Context, db string, collection string, validator primitive.D) (int64, int64, error) {
+	coll := d.client.Database(db).Collection(collection)
+	total, err := coll.CountDocuments(ctx, primitive.M{})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(validator) == 0 {
+		return total, 0, nil
+	}
+	invalid, err := coll.CountDocuments(ctx, primitive.M{"$nor": primitive.A{validator}})
+	if err != nil {
+		return 0, 0, err
+	}
+	return total, invalid, nil
+}
+
+// Validate checks if validation level and action are allowed values
+func (v CollectionValidation) Validate() error {
+	if !slices.Contains(ValidationLevels, v.Level) {
+		return fmt.Errorf("validationLevel must be one of %v, got %q", ValidationLevels, v.Level)
+	}
+	if !slices.Contains(ValidationActions, v.Action) {
+		return fmt.Errorf("validationAction must be one of %v, got %q", ValidationActions, v.Action)
+	}
+	return nil
+}
+
+// ToJson returns validation rules as indented extended JSON
+// that can be edited and parsed back with ParseCollectionValidation
+func (v CollectionValidation) ToJson() (string, error) {
+	jsoned, err := bson.MarshalExtJSONIndent(v.toDocument(), false, false, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsoned), nil
+}
+
+// ParseCollectionValidation parses validation rules from extended JSON
+func ParseCollectionValidation(text string) (CollectionValidation, error) {
+	validation := CollectionValidation{}
+	if err := bson.UnmarshalExtJSON([]byte(text), false, &validation); err != nil {
+		return CollectionValidation{}, fmt.Errorf("error parsing validation rules: %w", err)
+	}
+	validation.setDefaults()
+	return validation, validation.Validate()
+}
+
+func (v *CollectionValidation) setDefaults() {
+	if v.Validator == nil {
+		v.Validator = primitive.D{}
+	}
+	if v.Level == "" {
+		v.Level = "strict"
+	}
+	if v.Action == "" {
+		v.Action = "error"
+	}
+}
+
+func (v CollectionValidation) toDocument() primitive.D {
+	return primitive.D{
+		{Key: "validator", Value: v.Validator},
+		{Key: "validationLevel", Value: v.Level},
+		{Key: "validationAction", Value: v.Action},
+	}
+}
+
+func (v CollectionValidation) toAuditImage() primitive.M {
+	return primitive.M{"validator": v.Validator, "validationLevel": v.Level, "validationAction": v.Action}
+}
+
+func parseValidationResult(result primitive.M) *ValidationResult {
+	validation := &ValidationResult{
+		Records:          toInt64(result["nrecords"]),
+		InvalidDocuments: toInt64(result["nInvalidDocuments"]),
+		Warnings:         toStrings(result["warnings"]),
+		Errors:           toStrings(result["errors"]),
+	}
+	validation.Valid, _ = result["valid"].(bool)
+	return validation
+}
+
+func toStrings(value interface{}) []string {
+	values, _ := value.(primitive.A)
+	result := []string{}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCollectionValidationJsonRoundTrip(t *testing.T) {
+	validation := CollectionValidation{
+		Validator: primitive.D{{Key: "$jsonSchema", Value: primitive.D{
+			{Key: "bsonType", Value: "object"},
+			{Key: "required", Value: primitive.A{"name"}},
+		}}},
+		Level:  "moderate",
+		Action: "warn",
+	}
+
+	text, err := validation.ToJson()
+	assert.NoError(t, err)
+
+	parsed, err := ParseCollectionValidation(text)
+	assert.NoError(t, err)
+	assert.Equal(t, validation, parsed)
+}
+
+func TestParseCollectionValidationDefaults(t *testing.T) {
+	parsed, err := ParseCollectionValidation(`{"validator": {"qty": {"$gt": 0}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "strict", parsed.Level)
+	assert.Equal(t, "error", parsed.Action)
+	assert.Equal(t, primitive.D{{Key: "qty", Value: primitive.D{{Key: "$gt", Value: int32(0)}}}}, parsed.Validator)
+
+	parsed, err = ParseCollectionValidation(`{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.D{}, parsed.Validator)
+}
+
+func TestParseCollectionValidationErrors(t *testing.T) {
+	_, err := ParseCollectionValidation(`{"validationLevel": "always"}`)
+	assert.Error(t, err)
+
+	_, err = ParseCollectionValidation(`{"validationAction": "ignore"}`)
+	assert.Error(t, err)
+
+	_, err = ParseCollectionValidation(`{"validator": `)
+	assert.Error(t, err)
+}
+
+func TestParseValidationResult(t *testing.T) {
+	result := primitive.M{
+		"valid":             true,
+		"nrecords":          int32(10),
+		"nInvalidDocuments": int64(2),
+		"warnings":          primitive.A{"Detected 2 schema-invalid documents"},
+		"errors":            primitive.A{},
+	}
+
+	assert.Equal(t, &ValidationResult{
+		Valid:            true,
+		Records:          10,
+		InvalidDocuments: 2,
+		Warnings:         []string{"Detected 2 schema-invalid documents"},
+		Errors:           []string{},
+	}, parseValidationResult(result))
+}
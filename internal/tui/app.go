@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"reflect"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/kopecmaciej/vi-mongo/internal/config"
 	"github.com/kopecmaciej/vi-mongo/internal/mongo"
@@ -83,7 +85,7 @@ func (a *App) setKeybindings() {
 
 func (a *App) connectToMongo() error {
 	currConn := a.App.GetConfig().GetCurrentConnection()
-	if a.GetDao() != nil && *a.GetDao().Config == *currConn {
+	if a.GetDao() != nil && reflect.DeepEqual(*a.GetDao().Config, *currConn) {
 		return nil
 	}
 
@@ -94,7 +96,7 @@ func (a *App) connectToMongo() error {
 	if err := client.Ping(); err != nil {
 		return err
 	}
-	a.SetDao(mongo.NewDao(client.Client, client.Config))
+	a.SetDao(mongo.NewDao(client.Client, client.Config, a.App.GetConfig().Log))
 	return nil
 }
 
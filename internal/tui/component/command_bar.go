@@ -0,0 +1,232 @@
+package component
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	CommandBarComponent = "CommandBar"
+
+	commandHistoryFile = "command_history.txt"
+	maxCommandHistory  = 50
+)
+
+// CommandBar is a vim-like command line, commands are executed
+// by the function set with SetExecuteFunc
+type CommandBar struct {
+	*core.BaseElement
+	*core.InputField
+
+	history      []string
+	historyIndex int
+	// completions are cycled with each completion key press
+	completions     []string
+	completionIndex int
+
+	completeFunc func(line string) []string
+	executeFunc  func(line string)
+	closeFunc    func()
+}
+
+func NewCommandBar() *CommandBar {
+	c := &CommandBar{
+		BaseElement: core.NewBaseElement(),
+		InputField:  core.NewInputField(),
+	}
+
+	c.SetIdentifier(CommandBarComponent)
+	c.SetAfterInitFunc(c.init)
+
+	return c
+}
+
+func (c *CommandBar) init() error {
+	c.setStyle()
+	c.setStaticLayout()
+	c.setKeybindings()
+
+	c.handleEvents()
+
+	return nil
+}
+
+func (c *CommandBar) setStaticLayout() {
+	c.SetLabel(":")
+}
+
+func (c *CommandBar) setStyle() {
+	c.SetStyle(c.App.GetStyles())
+	style := &c.App.GetStyles().InputBar
+	c.SetLabelColor(style.LabelColor.Color())
+	c.SetFieldTextColor(style.InputColor.Color())
+}
+
+func (c *CommandBar) setKeybindings() {
+	k := c.App.GetKeys()
+	c.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if !k.Contains(k.CommandBar.Complete, event.Name()) {
+			c.completions = nil
+		}
+
+		switch {
+		case k.Contains(k.CommandBar.Complete, event.Name()):
+			c.complete()
+			return nil
+		case k.Contains(k.CommandBar.PreviousCommand, event.Name()):
+			c.moveInHistory(-1)
+			return nil
+		case k.Contains(k.CommandBar.NextCommand, event.Name()):
+			c.moveInHistory(1)
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			line := strings.TrimSpace(c.GetText())
+			c.close()
+			if line != "" {
+				c.saveToHistory(line)
+				if c.executeFunc != nil {
+					c.executeFunc(line)
+				}
+			}
+			return nil
+		case event.Key() == tcell.KeyEscape:
+			c.close()
+			return nil
+		}
+		return event
+	})
+}
+
+func (c *CommandBar) handleEvents() {
+	go c.HandleEvents(CommandBarComponent, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			c.setStyle()
+		}
+	})
+}
+
+// SetCompleteFunc sets the function that returns completions of the line
+func (c *CommandBar) SetCompleteFunc(f func(line string) []string) {
+	c.completeFunc = f
+}
+
+// SetExecuteFunc sets the function that executes accepted line
+func (c *CommandBar) SetExecuteFunc(f func(line string)) {
+	c.executeFunc = f
+}
+
+// SetCloseFunc sets the function called when command bar is closed
+func (c *CommandBar) SetCloseFunc(f func()) {
+	c.closeFunc = f
+}
+
+// Open clears the command bar and loads the history
+func (c *CommandBar) Open() {
+	c.Enable()
+	c.SetText("")
+	c.completions = nil
+
+	history, err := loadCommandHistory()
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading command history")
+	}
+	c.history = history
+	c.historyIndex = len(history)
+}
+
+func (c *CommandBar) close() {
+	c.Disable()
+	if c.closeFunc != nil {
+		c.closeFunc()
+	}
+}
+
+func (c *CommandBar) complete() {
+	if c.completeFunc == nil {
+		return
+	}
+	if c.completions == nil {
+		c.completions = c.completeFunc(c.GetText())
+		c.completionIndex = -1
+	}
+	if len(c.completions) == 0 {
+		return
+	}
+	c.completionIndex = (c.completionIndex + 1) % len(c.completions)
+	c.SetText(c.completions[c.completionIndex])
+}
+
+func (c *CommandBar) moveInHistory(delta int) {
+	index := c.historyIndex + delta
+	if index < 0 || index > len(c.history) {
+		return
+	}
+	c.historyIndex = index
+	if index == len(c.history) {
+		c.SetText("")
+		return
+	}
+	c.SetText(c.history[index])
+}
+
+func (c *CommandBar) saveToHistory(line string) {
+	history := []string{}
+	for _, entry := range c.history {
+		if entry != line {
+			history = append(history, entry)
+		}
+	}
+	history = append(history, line)
+	if len(history) > maxCommandHistory {
+		history = history[len(history)-maxCommandHistory:]
+	}
+	c.history = history
+
+	path, err := getCommandHistoryPath()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting command history path")
+		return
+	}
+	err = os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Error().Err(err).Msg("Error saving command history")
+	}
+}
+
+func loadCommandHistory() ([]string, error) {
+	path, err := getCommandHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	history := []string{}
+	for _, line := range strings.Split(string(bytes), "\n") {
+		if line != "" {
+			history = append(history, line)
+		}
+	}
+	return history, nil
+}
+
+func getCommandHistoryPath() (string, error) {
+	configDir, err := util.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, commandHistoryFile), nil
+}
@@ -3,7 +3,9 @@ package component
 import (
 	"context"
 	"fmt"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/atotto/clipboard"
@@ -14,7 +16,9 @@ import (
 	"github.com/kopecmaciej/vi-mongo/internal/mongo"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
 	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -24,6 +28,8 @@ const (
 	QueryBarComponent  = "QueryBar"
 	SortBarComponent   = "SortBar"
 	ContentDeleteModal = "ContentDeleteModal"
+	ContentEditModal   = "ContentEditModal"
+	ContentCopyModal   = "ContentCopyModal"
 )
 
 type ViewType int
@@ -39,19 +45,37 @@ type Content struct {
 	*core.BaseElement
 	*core.Flex
 
-	tableFlex   *core.Flex
-	tableHeader *core.TextView
-	table       *core.Table
-	view        *core.TextView
-	style       *config.ContentStyle
-	queryBar    *InputBar
-	sortBar     *InputBar
-	peeker      *Peeker
-	deleteModal *modal.Delete
-	docModifier *DocModifier
-	state       *mongo.CollectionState
-	stateMap    *mongo.StateMap
-	currentView ViewType
+	tableFlex    *core.Flex
+	tableHeader  *core.TextView
+	table        *core.Table
+	view         *core.TextView
+	style        *config.ContentStyle
+	queryBar     *InputBar
+	sortBar      *InputBar
+	peeker       *Peeker
+	deleteModal  *modal.Delete
+	journalModal *modal.Journal
+	editModal    *primitives.InputModal
+	columnsModal *modal.Columns
+	filterModal  *modal.QuickFilter
+	searchModal  *modal.Search
+	copyModal    *modal.Form
+	progress     *modal.Progress
+	docModifier  *DocModifier
+	state        *mongo.CollectionState
+	stateMap     *mongo.StateMap
+	currentView  ViewType
+	// flatten shows fields of nested objects as separate columns
+	flatten        bool
+	expandedArrays map[string]bool
+	// layouts of columns in table view, columns are names
+	// of all columns from the last rendered table
+	layouts *config.Layouts
+	columns []string
+	// search in loaded documents, matched cells are highlighted
+	search         *util.TextSearch
+	searchBackward bool
+	headerInfo     string
 }
 
 func NewContent() *Content {
@@ -59,18 +83,27 @@ func NewContent() *Content {
 		BaseElement: core.NewBaseElement(),
 		Flex:        core.NewFlex(),
 
-		tableFlex:   core.NewFlex(),
-		tableHeader: core.NewTextView(),
-		table:       core.NewTable(),
-		view:        core.NewTextView(),
-		queryBar:    NewInputBar(QueryBarComponent, "Query"),
-		sortBar:     NewInputBar(SortBarComponent, "Sort"),
-		peeker:      NewPeeker(),
-		deleteModal: modal.NewDeleteModal(ContentDeleteModal),
-		docModifier: NewDocModifier(),
-		state:       &mongo.CollectionState{},
-		stateMap:    mongo.NewStateMap(),
-		currentView: TableView,
+		tableFlex:    core.NewFlex(),
+		tableHeader:  core.NewTextView(),
+		table:        core.NewTable(),
+		view:         core.NewTextView(),
+		queryBar:     NewInputBar(QueryBarComponent, "Query"),
+		sortBar:      NewInputBar(SortBarComponent, "Sort"),
+		peeker:       NewPeeker(),
+		deleteModal:  modal.NewDeleteModal(ContentDeleteModal),
+		journalModal: modal.NewJournalModal(),
+		editModal:    primitives.NewInputModal(),
+		columnsModal: modal.NewColumnsModal(),
+		filterModal:  modal.NewQuickFilterModal(),
+		searchModal:  modal.NewSearchModal(),
+		copyModal:    modal.NewFormModal(ContentCopyModal),
+		progress:     modal.NewProgressModal(),
+		docModifier:  NewDocModifier(),
+		state:        &mongo.CollectionState{},
+		stateMap:     mongo.NewStateMap(),
+		currentView:  TableView,
+
+		expandedArrays: map[string]bool{},
 	}
 
 	c.SetIdentifier(ContentComponent)
@@ -85,6 +118,9 @@ func NewContent() *Content {
 func (c *Content) init() error {
 	ctx := context.Background()
 
+	c.flatten = c.App.GetConfig().Content.FlattenNested
+	c.layouts = c.loadLayouts()
+
 	c.setStaticLayout()
 	c.setStyle()
 	c.setKeybindings(ctx)
@@ -98,6 +134,24 @@ func (c *Content) init() error {
 	if err := c.deleteModal.Init(c.App); err != nil {
 		return err
 	}
+	if err := c.journalModal.Init(c.App); err != nil {
+		return err
+	}
+	if err := c.columnsModal.Init(c.App); err != nil {
+		return err
+	}
+	if err := c.filterModal.Init(c.App); err != nil {
+		return err
+	}
+	if err := c.searchModal.Init(c.App); err != nil {
+		return err
+	}
+	if err := c.copyModal.Init(c.App); err != nil {
+		return err
+	}
+	if err := c.progress.Init(c.App); err != nil {
+		return err
+	}
 	if err := c.queryBar.Init(c.App); err != nil {
 		return err
 	}
@@ -138,6 +192,7 @@ func (c *Content) UpdateDao(dao *mongo.Dao) {
 	c.table.Clear()
 	c.BaseElement.UpdateDao(dao)
 	c.docModifier.UpdateDao(dao)
+	c.peeker.UpdateDao(dao)
 }
 
 func (c *Content) setStyle() {
@@ -155,6 +210,11 @@ func (c *Content) setStyle() {
 
 	c.table.SetBordersColor(c.style.SeparatorColor.Color())
 	c.table.SetSeparator(c.style.SeparatorSymbol.Rune())
+
+	c.editModal.SetBorderColor(styles.Global.BorderColor.Color())
+	c.editModal.SetBackgroundColor(styles.Global.BackgroundColor.Color())
+	c.editModal.SetFieldTextColor(styles.Others.ModalTextColor.Color())
+	c.editModal.SetFieldBackgroundColor(styles.Global.ContrastBackgroundColor.Color())
 }
 
 func (c *Content) setStaticLayout() {
@@ -171,6 +231,9 @@ func (c *Content) setStaticLayout() {
 	c.view.SetTitleAlign(tview.AlignCenter)
 	c.view.SetBorderPadding(2, 0, 6, 0)
 
+	c.editModal.SetBorder(true)
+	c.editModal.SetTitle("Edit value")
+
 	c.Flex.SetDirection(tview.FlexRow)
 }
 
@@ -181,6 +244,16 @@ func (c *Content) setKeybindings(ctx context.Context) {
 		row, coll := c.table.GetSelection()
 		c.handleScrolling(row)
 		switch {
+		case k.Contains(k.Content.Search, event.Name()):
+			return c.handleSearch(false)
+		case k.Contains(k.Content.SearchBackward, event.Name()):
+			return c.handleSearch(true)
+		case c.search != nil && k.Contains(k.Content.NextMatch, event.Name()):
+			return c.handleJumpToMatch(false)
+		case c.search != nil && k.Contains(k.Content.PreviousMatch, event.Name()):
+			return c.handleJumpToMatch(true)
+		case c.search != nil && event.Key() == tcell.KeyEscape:
+			return c.handleClearSearch()
 		case k.Contains(k.Content.ChangeView, event.Name()):
 			return c.handleSwitchView(ctx)
 		case k.Contains(k.Content.PeekDocument, event.Name()):
@@ -189,6 +262,22 @@ func (c *Content) setKeybindings(ctx context.Context) {
 			return c.handleViewDocument(row, coll)
 		case k.Contains(k.Content.AddDocument, event.Name()):
 			return c.handleAddDocument(ctx)
+		case k.Contains(k.Content.ToggleFlatten, event.Name()):
+			return c.handleToggleFlatten(ctx)
+		case k.Contains(k.Content.ExpandArray, event.Name()):
+			return c.handleExpandArray(ctx, row, coll)
+		case k.Contains(k.Content.FollowReference, event.Name()):
+			return c.handleFollowReference(ctx, row, coll)
+		case k.Contains(k.Content.QuickFilter, event.Name()):
+			return c.handleQuickFilter(ctx, row, coll)
+		case k.Contains(k.Content.ShowColumns, event.Name()):
+			return c.handleShowColumns(ctx)
+		case k.Contains(k.Content.WidenColumn, event.Name()):
+			return c.handleResizeColumn(ctx, coll, 5)
+		case k.Contains(k.Content.NarrowColumn, event.Name()):
+			return c.handleResizeColumn(ctx, coll, -5)
+		case k.Contains(k.Content.InlineEdit, event.Name()):
+			return c.handleInlineEdit(ctx, row, coll)
 		case k.Contains(k.Content.EditDocument, event.Name()):
 			return c.handleEditDocument(ctx, row, coll)
 		case k.Contains(k.Content.DuplicateDocument, event.Name()):
@@ -202,6 +291,8 @@ func (c *Content) setKeybindings(ctx context.Context) {
 		// TODO: Add automatic sort by given column
 		case k.Contains(k.Content.Refresh, event.Name()):
 			return c.handleRefresh(ctx)
+		case k.Contains(k.Content.CopyCollection, event.Name()):
+			return c.handleCopyCollection()
 		case k.Contains(k.Content.NextPage, event.Name()):
 			return c.handleNextPage(ctx)
 		case k.Contains(k.Content.NextDocument, event.Name()):
@@ -217,6 +308,12 @@ func (c *Content) setKeybindings(ctx context.Context) {
 		// 	return c.handleClearSelection()
 		case k.Contains(k.Content.CopyLine, event.Name()):
 			return c.handleCopyLine(row, coll)
+		case k.Contains(k.Content.Undo, event.Name()):
+			return c.handleUndo(ctx)
+		case k.Contains(k.Content.Redo, event.Name()):
+			return c.handleRedo(ctx)
+		case k.Contains(k.Content.ShowJournal, event.Name()):
+			return c.handleShowJournal(ctx)
 		case k.Contains(k.Content.CopyDocument, event.Name()):
 			return c.handleCopyDocument(row, coll)
 		}
@@ -229,6 +326,7 @@ func (c *Content) setKeybindings(ctx context.Context) {
 func (c *Content) HandleDatabaseSelection(ctx context.Context, db, coll string) error {
 	c.queryBar.SetText("")
 	c.sortBar.SetText("")
+	c.search = nil
 
 	state, ok := c.stateMap.Get(c.stateMap.Key(db, coll))
 	if ok {
@@ -252,6 +350,103 @@ func (c *Content) HandleDatabaseSelection(ctx context.Context, db, coll string)
 	return nil
 }
 
+// OpenWithQuery opens the collection with query bar shown and
+// prefilled with the filter, so it can be changed before it's applied
+func (c *Content) OpenWithQuery(ctx context.Context, db, coll, filter string) error {
+	if err := c.HandleDatabaseSelection(ctx, db, coll); err != nil {
+		return err
+	}
+	c.queryBar.SetText(filter)
+	c.queryBar.Enable()
+	c.Render(true)
+	return nil
+}
+
+// GetCollection returns database and collection of displayed documents
+func (c *Content) GetCollection() (string, string) {
+	return c.state.Db, c.state.Coll
+}
+
+// Refresh reloads documents of displayed collection
+func (c *Content) Refresh(ctx context.Context) error {
+	return c.updateContent(ctx, false)
+}
+
+// ApplyFilter sets the filter of displayed collection and reloads documents
+func (c *Content) ApplyFilter(ctx context.Context, filter string) error {
+	if _, err := mongo.ParseStringQuery(filter); err != nil {
+		return err
+	}
+	c.state.UpdateFilter(filter)
+	c.queryBar.SetText(c.state.Filter)
+	c.stateMap.Set(c.stateMap.Key(c.state.Db, c.state.Coll), c.state)
+	return c.updateContent(ctx, false)
+}
+
+// ApplySort sets the sort of displayed collection and reloads documents
+func (c *Content) ApplySort(ctx context.Context, sort string) error {
+	if _, err := mongo.ParseStringQuery(sort); err != nil {
+		return err
+	}
+	c.state.UpdateSort(sort)
+	c.sortBar.SetText(c.state.Sort)
+	c.stateMap.Set(c.stateMap.Key(c.state.Db, c.state.Coll), c.state)
+	return c.updateContent(ctx, false)
+}
+
+// SetLimit changes number of documents loaded per page
+func (c *Content) SetLimit(ctx context.Context, limit int64) error {
+	c.state.Limit = limit
+	c.state.Page = 0
+	c.stateMap.Set(c.stateMap.Key(c.state.Db, c.state.Coll), c.state)
+	return c.updateContent(ctx, false)
+}
+
+// SetPage loads given page, pages are counted from 1
+func (c *Content) SetPage(ctx context.Context, page int64) error {
+	if (page-1)*c.state.Limit >= c.state.Count && page > 1 {
+		return fmt.Errorf("page %d is out of range", page)
+	}
+	c.state.Page = (page - 1) * c.state.Limit
+	c.stateMap.Set(c.stateMap.Key(c.state.Db, c.state.Coll), c.state)
+	return c.updateContent(ctx, false)
+}
+
+// ExportDocuments saves all documents matching current filter and sort
+// as JSON array into the file, returns number of exported documents
+func (c *Content) ExportDocuments(ctx context.Context, path string) (int, error) {
+	filter, err := mongo.ParseFilter(c.state.Filter)
+	if err != nil {
+		return 0, err
+	}
+	sort, err := mongo.ParseStringQuery(c.state.Sort)
+	if err != nil {
+		return 0, err
+	}
+	documents, err := c.Dao.FindDocuments(ctx, c.state.Db, c.state.Coll, filter, sort)
+	if err != nil {
+		return 0, err
+	}
+
+	jsoned := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		docJson, err := mongo.ParseBsonDocument(doc)
+		if err != nil {
+			return 0, err
+		}
+		jsoned = append(jsoned, docJson)
+	}
+	indented, err := mongo.IndentJson("[" + strings.Join(jsoned, ",") + "]")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.WriteFile(path, indented.Bytes(), 0644); err != nil {
+		return 0, err
+	}
+	return len(documents), nil
+}
+
 // Rendering methods
 
 func (c *Content) Render(setFocus bool) {
@@ -282,12 +477,30 @@ func (c *Content) Render(setFocus bool) {
 }
 
 func (c *Content) renderTableView(startRow int, documents []primitive.M) {
-	c.table.SetFixed(1, 0)
-	sortedKeys := util.GetSortedKeysWithTypes(documents, c.style.ColumnTypeColor.Color().String())
+	rows := documents
+	if c.flatten {
+		rows = make([]primitive.M, len(documents))
+		for i, doc := range documents {
+			rows[i] = util.FlattenDocument(doc, c.App.GetConfig().Content.GetFlattenDepth(), c.expandedArrays)
+		}
+	}
+	keysWithTypes := util.GetSortedKeysWithTypes(rows, c.style.ColumnTypeColor.Color().String())
+	headers := make(map[string]string, len(keysWithTypes))
+	c.columns = make([]string, 0, len(keysWithTypes))
+	for _, keyWithType := range keysWithTypes {
+		key := strings.Split(keyWithType, " ")[0]
+		headers[key] = keyWithType
+		c.columns = append(c.columns, key)
+	}
+
+	layout := c.layouts.Get(c.state.Db, c.state.Coll)
+	visibleKeys := layout.Visible(c.columns)
+	c.table.SetFixed(1, layout.PinnedCount(c.columns))
 
 	// Set the header row
-	for col, key := range sortedKeys {
-		c.table.SetCell(startRow, col, tview.NewTableCell(key).
+	for col, key := range visibleKeys {
+		c.table.SetCell(startRow, col, tview.NewTableCell(headers[key]).
+			SetReference(headers[key]).
 			SetTextColor(c.style.ColumnKeyColor.Color()).
 			SetSelectable(false).
 			SetBackgroundColor(c.style.HeaderRowBackgroundColor.Color()).
@@ -296,25 +509,29 @@ func (c *Content) renderTableView(startRow int, documents []primitive.M) {
 	startRow++
 
 	// Populate the table with document values
-	for row, doc := range documents {
-		for col, key := range sortedKeys {
+	for row, doc := range rows {
+		for col, key := range visibleKeys {
 			var cellText string
-			if val, ok := doc[strings.Split(key, " ")[0]]; ok {
+			if val, ok := doc[key]; ok {
 				cellText = util.GetValueByType(val)
+				if arr, ok := val.(primitive.A); ok && c.flatten {
+					cellText = fmt.Sprintf("Array(%d)", len(arr))
+				}
 			} else {
 				cellText = ""
 			}
-			if len(cellText) > 30 {
-				cellText = cellText[0:30] + "..."
+			width := layout.Width(key)
+			if len(cellText) > width {
+				cellText = cellText[0:width] + "..."
 			}
 
 			cell := tview.NewTableCell(cellText).
 				SetAlign(tview.AlignLeft).
-				SetMaxWidth(30)
+				SetMaxWidth(width)
 
 			// we'll set reference to _id for first column to not repeat the same _id in whole row
 			if col == 0 {
-				cell.SetReference(doc["_id"])
+				cell.SetReference(documents[row]["_id"])
 			}
 			c.table.SetCell(startRow+row, col, cell)
 		}
@@ -359,7 +576,7 @@ func (c *Content) renderSingleRowView(startRow int, documents []primitive.M) {
 }
 
 func (c *Content) listDocuments(ctx context.Context) ([]primitive.M, int64, error) {
-	filter, err := mongo.ParseStringQuery(c.state.Filter)
+	filter, err := mongo.ParseFilter(c.state.Filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -455,6 +672,7 @@ func (c *Content) updateContent(ctx context.Context, useState bool) error {
 		headerInfo += fmt.Sprintf(" | Sort: %s", c.state.Sort)
 		c.sortBar.SetText(c.state.Sort)
 	}
+	c.headerInfo = headerInfo
 	c.tableHeader.SetText(headerInfo)
 
 	c.stateMap.Set(c.stateMap.Key(c.state.Db, c.state.Coll), c.state)
@@ -474,6 +692,7 @@ func (c *Content) updateContent(ctx context.Context, useState bool) error {
 	case SingleLineView:
 		c.renderSingleRowView(startRow, documents)
 	}
+	c.highlightMatches()
 
 	return nil
 }
@@ -608,11 +827,19 @@ func (c *Content) deleteDocument(ctx context.Context, jsonString string) error {
 			return
 		}
 		if buttonLabel == "Delete" {
-			err = c.Dao.DeleteDocument(ctx, c.state.Db, c.state.Coll, objectId)
+			// deleted document is recorded as it was stored, loaded one may be stale
+			deleted, err := c.Dao.DeleteDocument(ctx, c.state.Db, c.state.Coll, objectId)
 			if err != nil {
 				modal.ShowError(c.App.Pages, "Error deleting document", err)
 				return
 			}
+			c.App.GetJournal().Record(mongo.JournalEntry{
+				Type:   mongo.DeleteOperation,
+				Db:     c.state.Db,
+				Coll:   c.state.Coll,
+				Id:     objectId,
+				Before: deleted,
+			})
 			c.state.DeleteDoc(objectId)
 		}
 
@@ -704,6 +931,9 @@ func (c *Content) handleAddDocument(ctx context.Context) *tcell.EventKey {
 		modal.ShowError(c.App.Pages, "Error adding document", err)
 		return nil
 	}
+	if id == primitive.NilObjectID {
+		return nil
+	}
 	insertedDoc, err := c.Dao.GetDocument(ctx, c.state.Db, c.state.Coll, id)
 	if err != nil {
 		modal.ShowError(c.App.Pages, "Error getting inserted document", err)
@@ -721,18 +951,319 @@ func (c *Content) handleEditDocument(ctx context.Context, row, coll int) *tcell.
 		modal.ShowError(c.App.Pages, "Error getting document", err)
 		return nil
 	}
-	updated, err := c.docModifier.Edit(ctx, c.state.Db, c.state.Coll, _id, doc)
+	err = c.docModifier.Edit(ctx, c.state.Db, c.state.Coll, _id, doc, func(updated string) {
+		c.refreshDocument(ctx, updated)
+	})
 	if err != nil {
 		modal.ShowError(c.App.Pages, "Error editing document", err)
+	}
+	return nil
+}
+
+// handleInlineEdit shows input with the value of selected cell
+// and sets the field to the new value
+func (c *Content) handleInlineEdit(ctx context.Context, row, col int) *tcell.EventKey {
+	if c.currentView != TableView {
+		modal.ShowInfo(c.App.Pages, "Inline editing is available only in table view")
+		return nil
+	}
+	header, ok := c.table.GetCell(0, col).GetReference().(string)
+	if !ok {
+		return nil
+	}
+	key, mongoType := parseColumnHeader(header)
+	if key == "_id" || strings.HasPrefix(key, "_id.") {
+		modal.ShowInfo(c.App.Pages, "_id field cannot be modified")
 		return nil
 	}
 
-	if updated != "" {
-		c.refreshDocument(ctx, updated)
+	doc := c.state.GetDocById(c.getDocumentId(row, col))
+	if doc == nil {
+		return nil
+	}
+
+	text := ""
+	if value, ok := util.GetValueByPath(doc, key); ok {
+		mongoType = util.GetMongoType(value)
+		text = util.GetValueByType(value)
+	}
+	switch mongoType {
+	case util.TypeArray, util.TypeObject, util.TypeMixed:
+		modal.ShowInfo(c.App.Pages, "Only scalar values can be edited inline")
+		return nil
+	}
+
+	c.editModal.SetLabel(fmt.Sprintf("Edit [::b]%s[::-] (%s)", key, mongoType))
+	c.editModal.SetText(text)
+	c.editModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			c.saveInlineEdit(ctx, doc, key, mongoType)
+			return nil
+		case tcell.KeyEscape:
+			c.App.Pages.RemovePage(ContentEditModal)
+			return nil
+		}
+		return event
+	})
+	c.App.Pages.AddPage(ContentEditModal, c.editModal, true, true)
+
+	return nil
+}
+
+func (c *Content) saveInlineEdit(ctx context.Context, doc primitive.M, key, mongoType string) {
+	value, err := util.ParseValueByType(c.editModal.GetText(), mongoType)
+	if err != nil {
+		modal.ShowError(c.App.Pages, "Invalid value", err)
+		return
+	}
+	c.App.Pages.RemovePage(ContentEditModal)
+
+	updated, err := c.docModifier.UpdateField(ctx, c.state.Db, c.state.Coll, doc, key, value)
+	if err != nil {
+		modal.ShowError(c.App.Pages, "Error updating value", err)
+		return
+	}
+	updatedJson, err := mongo.ParseBsonDocument(updated)
+	if err != nil {
+		modal.ShowError(c.App.Pages, "Error updating value", err)
+		return
+	}
+
+	row, col := c.table.GetSelection()
+	c.refreshDocument(ctx, updatedJson)
+	c.table.Select(row, col)
+}
+
+func (c *Content) handleToggleFlatten(ctx context.Context) *tcell.EventKey {
+	c.flatten = !c.flatten
+	c.updateContent(ctx, true)
+	return nil
+}
+
+// handleExpandArray shows elements of the array as separate columns
+// or collapses them back if the column belongs to expanded array
+func (c *Content) handleExpandArray(ctx context.Context, row, col int) *tcell.EventKey {
+	if !c.flatten || c.currentView != TableView {
+		modal.ShowInfo(c.App.Pages, "Arrays can be expanded only when nested columns are shown")
+		return nil
 	}
+	header, ok := c.table.GetCell(0, col).GetReference().(string)
+	if !ok {
+		return nil
+	}
+	key, _ := parseColumnHeader(header)
+
+	expanded := ""
+	for path := range c.expandedArrays {
+		if strings.HasPrefix(key, path+".") && len(path) > len(expanded) {
+			expanded = path
+		}
+	}
+	if expanded != "" {
+		delete(c.expandedArrays, expanded)
+		c.updateContent(ctx, true)
+		return nil
+	}
+
+	doc := c.state.GetDocById(c.getDocumentId(row, col))
+	value, _ := util.GetValueByPath(doc, key)
+	if _, ok := value.(primitive.A); !ok {
+		modal.ShowInfo(c.App.Pages, "Selected value is not an array")
+		return nil
+	}
+	c.expandedArrays[key] = true
+	c.updateContent(ctx, true)
+
 	return nil
 }
 
+// handleFollowReference opens the document referenced by selected ObjectID in the peeker
+func (c *Content) handleFollowReference(ctx context.Context, row, col int) *tcell.EventKey {
+	doc := c.state.GetDocById(c.getDocumentId(row, col))
+	if doc == nil {
+		return nil
+	}
+
+	var field string
+	var id primitive.ObjectID
+	switch c.currentView {
+	case TableView:
+		header, ok := c.table.GetCell(0, col).GetReference().(string)
+		if !ok {
+			return nil
+		}
+		field, _ = parseColumnHeader(header)
+		value, _ := util.GetValueByPath(doc, field)
+		id, ok = value.(primitive.ObjectID)
+		if !ok {
+			modal.ShowInfo(c.App.Pages, "Selected value is not an ObjectID")
+			return nil
+		}
+	case JsonView:
+		line := c.table.GetCell(row, col).Text
+		var ok bool
+		id, ok = mongo.ExtractObjectID(line)
+		if !ok {
+			modal.ShowInfo(c.App.Pages, "Selected line does not contain an ObjectID")
+			return nil
+		}
+		key, _, _ := strings.Cut(line, ":")
+		field = mongo.FindFieldPath(doc, strings.Trim(key, `" `), id)
+	default:
+		modal.ShowInfo(c.App.Pages, "References can be followed only in table and JSON view")
+		return nil
+	}
+
+	reference, err := c.Dao.FindReference(ctx, c.state.Db, c.state.Coll, field, id)
+	if err != nil {
+		modal.ShowError(c.App.Pages, "Error following reference", err)
+		return nil
+	}
+	if err := c.peeker.RenderReference(ctx, reference); err != nil {
+		modal.ShowError(c.App.Pages, "Error showing referenced document", err)
+	}
+	return nil
+}
+
+// handleQuickFilter shows conditions that can be built from the selected cell
+// and adds the chosen one to the current filter
+func (c *Content) handleQuickFilter(ctx context.Context, row, col int) *tcell.EventKey {
+	if c.currentView != TableView {
+		modal.ShowInfo(c.App.Pages, "Quick filter is available only in table view")
+		return nil
+	}
+	header, ok := c.table.GetCell(0, col).GetReference().(string)
+	if !ok {
+		return nil
+	}
+	key, _ := parseColumnHeader(header)
+	doc := c.state.GetDocById(c.getDocumentId(row, col))
+	if doc == nil {
+		return nil
+	}
+	value, exists := util.GetValueByPath(doc, key)
+
+	options := []modal.FilterOption{}
+	if exists {
+		text := util.GetValueByType(value)
+		if len(text) > 30 {
+			text = text[0:30] + "..."
+		}
+		options = append(options,
+			modal.FilterOption{Label: fmt.Sprintf("%s = %s", key, text), Operator: mongo.FilterEquals},
+			modal.FilterOption{Label: fmt.Sprintf("%s != %s", key, text), Operator: mongo.FilterNotEquals},
+		)
+		switch util.GetMongoType(value) {
+		case util.TypeInt, util.TypeLong, util.TypeDouble, util.TypeDecimal, util.TypeString, util.TypeDate, util.TypeObjectId:
+			options = append(options,
+				modal.FilterOption{Label: fmt.Sprintf("%s > %s", key, text), Operator: mongo.FilterGreaterThan},
+				modal.FilterOption{Label: fmt.Sprintf("%s < %s", key, text), Operator: mongo.FilterLessThan},
+			)
+		}
+		options = append(options, modal.FilterOption{Label: fmt.Sprintf("%s exists", key), Operator: mongo.FilterExists})
+	} else {
+		options = append(options,
+			modal.FilterOption{Label: fmt.Sprintf("%s exists", key), Operator: mongo.FilterExists},
+			modal.FilterOption{Label: fmt.Sprintf("%s does not exist", key), Operator: mongo.FilterNotExists},
+		)
+	}
+
+	c.filterModal.Render(options, func(option modal.FilterOption) {
+		conditionValue := value
+		switch value.(type) {
+		case primitive.M, primitive.A:
+			if option.Operator != mongo.FilterEquals && option.Operator != mongo.FilterNotEquals {
+				break
+			}
+			// loaded documents don't keep the order of embedded fields
+			var err error
+			conditionValue, err = c.Dao.GetFieldValue(ctx, c.state.Db, c.state.Coll, doc["_id"], key)
+			if err != nil {
+				modal.ShowError(c.App.Pages, "Error building filter", err)
+				return
+			}
+		}
+		condition, err := mongo.BuildCondition(key, option.Operator, conditionValue)
+		if err != nil {
+			modal.ShowError(c.App.Pages, "Error building filter", err)
+			return
+		}
+		filter, err := mongo.MergeFilter(c.state.Filter, condition)
+		if err != nil {
+			modal.ShowError(c.App.Pages, "Error building filter", err)
+			return
+		}
+		if err := c.ApplyFilter(ctx, filter); err != nil {
+			modal.ShowError(c.App.Pages, "Error filtering documents", err)
+		}
+	})
+
+	return nil
+}
+
+func (c *Content) handleShowColumns(ctx context.Context) *tcell.EventKey {
+	if c.currentView != TableView {
+		modal.ShowInfo(c.App.Pages, "Columns can be managed only in table view")
+		return nil
+	}
+	layout := c.layouts.Get(c.state.Db, c.state.Coll)
+	c.columnsModal.Render(layout, c.columns, func() {
+		c.saveLayouts()
+		c.updateContent(ctx, true)
+	})
+	return nil
+}
+
+func (c *Content) handleResizeColumn(ctx context.Context, col, delta int) *tcell.EventKey {
+	if c.currentView != TableView {
+		return nil
+	}
+	header, ok := c.table.GetCell(0, col).GetReference().(string)
+	if !ok {
+		return nil
+	}
+	key, _ := parseColumnHeader(header)
+	c.layouts.Get(c.state.Db, c.state.Coll).Resize(key, delta)
+	c.saveLayouts()
+
+	row, _ := c.table.GetSelection()
+	c.updateContent(ctx, true)
+	c.table.Select(row, col)
+	return nil
+}
+
+// loadLayouts loads saved column layouts, if they can't be loaded
+// layouts start empty and will overwrite the file on first change
+func (c *Content) loadLayouts() *config.Layouts {
+	path, err := config.GetLayoutsPath()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting layouts path")
+		return config.NewLayouts(path)
+	}
+	layouts, err := config.LoadLayouts(path)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading column layouts")
+		return config.NewLayouts(path)
+	}
+	return layouts
+}
+
+func (c *Content) saveLayouts() {
+	if err := c.layouts.Save(); err != nil {
+		modal.ShowError(c.App.Pages, "Error saving columns layout", err)
+	}
+}
+
+// parseColumnHeader returns field name and type from the table header
+func parseColumnHeader(header string) (string, string) {
+	key, mongoType, _ := strings.Cut(header, " ")
+	if i := strings.LastIndex(mongoType, "]"); i >= 0 {
+		mongoType = mongoType[i+1:]
+	}
+	return key, mongoType
+}
+
 func (c *Content) handleDuplicateDocument(ctx context.Context, row, coll int) *tcell.EventKey {
 	doc, err := c.getDocumentBasedOnView(row, coll)
 	if err != nil {
@@ -744,6 +1275,9 @@ func (c *Content) handleDuplicateDocument(ctx context.Context, row, coll int) *t
 		modal.ShowError(c.App.Pages, "Error duplicating document", err)
 		return nil
 	}
+	if id == primitive.NilObjectID {
+		return nil
+	}
 	duplicatedDoc, err := c.Dao.GetDocument(ctx, c.state.Db, c.state.Coll, id)
 	if err != nil {
 		modal.ShowError(c.App.Pages, "Error getting inserted document", err)
@@ -788,6 +1322,158 @@ func (c *Content) handleDeleteDocument(ctx context.Context, row, coll int) *tcel
 	return nil
 }
 
+func (c *Content) handleUndo(ctx context.Context) *tcell.EventKey {
+	c.undoOperation(ctx, 0)
+	return nil
+}
+
+func (c *Content) handleRedo(ctx context.Context) *tcell.EventKey {
+	journal := c.App.GetJournal()
+	entry, ok := journal.TakeRedo()
+	if !ok {
+		modal.ShowInfo(c.App.Pages, "Nothing to redo")
+		return nil
+	}
+	redone, err := c.Dao.RedoOperation(ctx, entry)
+	if err != nil {
+		journal.PushRedo(entry)
+		modal.ShowError(c.App.Pages, "Error redoing "+string(entry.Type), err)
+		return nil
+	}
+	journal.PushUndo(redone)
+	c.refreshAfterJournalChange(ctx, entry)
+	return nil
+}
+
+func (c *Content) handleShowJournal(ctx context.Context) *tcell.EventKey {
+	c.journalModal.Render(c.App.GetJournal().Entries(), func(index int) {
+		c.undoOperation(ctx, index)
+	})
+	return nil
+}
+
+// undoOperation reverts the operation from the journal, index is counted
+// from the most recent operation
+func (c *Content) undoOperation(ctx context.Context, index int) {
+	journal := c.App.GetJournal()
+	entry, ok := journal.TakeUndo(index)
+	if !ok {
+		modal.ShowInfo(c.App.Pages, "Nothing to undo")
+		return
+	}
+	undone, err := c.Dao.UndoOperation(ctx, entry)
+	if err != nil {
+		journal.RestoreUndo(index, entry)
+		modal.ShowError(c.App.Pages, "Error undoing "+string(entry.Type), err)
+		return
+	}
+	journal.PushRedo(undone)
+	c.refreshAfterJournalChange(ctx, entry)
+}
+
+// refreshAfterJournalChange reloads documents if the operation
+// was made on currently displayed collection
+func (c *Content) refreshAfterJournalChange(ctx context.Context, entry mongo.JournalEntry) {
+	if entry.Db != c.state.Db || entry.Coll != c.state.Coll {
+		return
+	}
+	err := c.updateContent(ctx, false)
+	if err != nil {
+		modal.ShowError(c.App.Pages, "Error refreshing documents", err)
+	}
+}
+
+func (c *Content) handleSearch(backward bool) *tcell.EventKey {
+	c.searchModal.Render(c.search, backward, func(search *util.TextSearch, backward bool) {
+		c.search = search
+		c.searchBackward = backward
+		c.highlightMatches()
+		if search != nil {
+			c.handleJumpToMatch(false)
+		}
+	})
+	return nil
+}
+
+// handleJumpToMatch selects the next cell that matches the search, reverse
+// moves in the opposite direction than the search was started
+func (c *Content) handleJumpToMatch(reverse bool) *tcell.EventKey {
+	matches := c.searchMatches()
+	cols := c.table.GetColumnCount()
+	row, col := c.table.GetSelection()
+	next := util.NextMatch(matches, row*cols+col, c.searchBackward != reverse)
+	if next == -1 {
+		modal.ShowInfo(c.App.Pages, "Pattern not found: "+c.search.Pattern)
+		return nil
+	}
+	c.table.Select(matches[next]/cols, matches[next]%cols)
+	c.setSearchHeader(matches)
+	return nil
+}
+
+func (c *Content) handleClearSearch() *tcell.EventKey {
+	c.search = nil
+	c.highlightMatches()
+	return nil
+}
+
+// searchMatches returns positions of selectable cells that match the search,
+// position is counted row by row as row*columns+column
+func (c *Content) searchMatches() []int {
+	matches := []int{}
+	if c.search == nil {
+		return matches
+	}
+	cols := c.table.GetColumnCount()
+	for row := 0; row < c.table.GetRowCount(); row++ {
+		for col := 0; col < cols; col++ {
+			cell := c.table.GetCell(row, col)
+			if cell.NotSelectable || cell.Text == "" || !c.search.Matches(cell.Text) {
+				continue
+			}
+			matches = append(matches, row*cols+col)
+		}
+	}
+	return matches
+}
+
+// highlightMatches sets background of the text that matches the search,
+// cells are highlighted only when drawn, so their text stays untouched
+func (c *Content) highlightMatches() {
+	if c.search == nil {
+		c.table.SetHighlightFunc(nil)
+	} else {
+		search, startTag := c.search, fmt.Sprintf("[:%s]", c.style.SearchMatchColor.Color().CSS())
+		c.table.SetHighlightFunc(func(cell *tview.TableCell) string {
+			if cell.NotSelectable {
+				return cell.Text
+			}
+			return search.Highlight(cell.Text, startTag, "[:-]")
+		})
+	}
+	c.setSearchHeader(c.searchMatches())
+}
+
+func (c *Content) setSearchHeader(matches []int) {
+	if c.search == nil {
+		c.tableHeader.SetText(c.headerInfo)
+		return
+	}
+	row, col := c.table.GetSelection()
+	status := searchStatus(matches, row*c.table.GetColumnCount()+col)
+	c.tableHeader.SetText(fmt.Sprintf("%s | Search: %s (%s)", c.headerInfo, tview.Escape(c.search.Pattern), status))
+}
+
+// searchStatus describes the position of current match, like "match 2 of 5"
+func searchStatus(matches []int, current int) string {
+	for i, match := range matches {
+		if match == current {
+			return fmt.Sprintf("match %d of %d", i+1, len(matches))
+		}
+	}
+	return fmt.Sprintf("%d matches", len(matches))
+}
+
 func (c *Content) handleRefresh(ctx context.Context) *tcell.EventKey {
 	err := c.updateContent(ctx, false)
 	if err != nil {
@@ -796,6 +1482,117 @@ func (c *Content) handleRefresh(ctx context.Context) *tcell.EventKey {
 	return nil
 }
 
+// handleCopyCollection shows the form for copying displayed collection
+// to other database or connection
+func (c *Content) handleCopyCollection() *tcell.EventKey {
+	if c.state.Coll == "" {
+		return nil
+	}
+	db, coll := c.state.Db, c.state.Coll
+	connections := []string{c.Dao.Config.Name}
+	for _, conn := range c.App.GetConfig().Connections {
+		if conn.Name != c.Dao.Config.Name {
+			connections = append(connections, conn.Name)
+		}
+	}
+
+	c.copyModal.Render(fmt.Sprintf("Copy %s.%s", db, coll), "Copy", func(form *tview.Form) {
+		form.AddDropDown("Connection", connections, 0, nil)
+		form.AddInputField("Database", db, 40, nil, nil)
+		form.AddInputField("Collection", coll+"_copy", 40, nil, nil)
+		form.AddCheckbox("Only matching query", c.state.Filter != "", nil)
+		form.AddCheckbox("Copy indexes", true, nil)
+		form.AddCheckbox("Drop target first", false, nil)
+		form.AddInputField("Batch size", "1000", 10, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) error {
+		_, connName := form.GetFormItemByLabel("Connection").(*tview.DropDown).GetCurrentOption()
+		targetDb := strings.TrimSpace(form.GetFormItemByLabel("Database").(*tview.InputField).GetText())
+		targetColl := strings.TrimSpace(form.GetFormItemByLabel("Collection").(*tview.InputField).GetText())
+		if targetDb == "" || targetColl == "" {
+			return fmt.Errorf("target database and collection are required")
+		}
+		batchSize, err := strconv.Atoi(form.GetFormItemByLabel("Batch size").(*tview.InputField).GetText())
+		if err != nil || batchSize <= 0 {
+			return fmt.Errorf("batch size must be a positive number")
+		}
+		opts := mongo.CopyOptions{
+			BatchSize:   batchSize,
+			CopyIndexes: form.GetFormItemByLabel("Copy indexes").(*tview.Checkbox).IsChecked(),
+			DropTarget:  form.GetFormItemByLabel("Drop target first").(*tview.Checkbox).IsChecked(),
+		}
+		if form.GetFormItemByLabel("Only matching query").(*tview.Checkbox).IsChecked() {
+			if opts.Filter, err = mongo.ParseFilter(c.state.Filter); err != nil {
+				return err
+			}
+		}
+
+		target, closeTarget, err := c.connectTo(connName)
+		if err != nil {
+			return err
+		}
+		// progress is shown after the form is closed, so it keeps the focus
+		go c.App.QueueUpdateDraw(func() {
+			c.copyCollection(db, coll, target, targetDb, targetColl, opts, closeTarget)
+		})
+		return nil
+	})
+
+	return nil
+}
+
+// connectTo returns dao of the saved connection, current dao is
+// returned for current connection, close disconnects other connections
+func (c *Content) connectTo(name string) (dao *mongo.Dao, close func(), err error) {
+	if name == c.Dao.Config.Name {
+		return c.Dao, func() {}, nil
+	}
+	for _, conn := range c.App.GetConfig().Connections {
+		if conn.Name != name {
+			continue
+		}
+		client := mongo.NewClient(&conn)
+		if err := client.Connect(); err != nil {
+			return nil, nil, err
+		}
+		if err := client.Ping(); err != nil {
+			client.Close(context.Background())
+			return nil, nil, err
+		}
+		return mongo.NewDao(client.Client, client.Config, c.App.GetConfig().Log), func() { client.Close(context.Background()) }, nil
+	}
+	return nil, nil, fmt.Errorf("connection %s not found", name)
+}
+
+// copyCollection copies documents in the background, showing its progress
+func (c *Content) copyCollection(db, coll string, target *mongo.Dao, targetDb, targetColl string, opts mongo.CopyOptions, closeTarget func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	title := fmt.Sprintf("Copying %s.%s to %s.%s", db, coll, targetDb, targetColl)
+	c.progress.Render(title, "Counting documents...", cancel)
+
+	go func() {
+		defer closeTarget()
+		var copied int64
+		err := c.Dao.CopyCollection(ctx, db, coll, target, targetDb, targetColl, opts, func(progress mongo.CopyProgress) {
+			copied = progress.Copied
+			c.App.QueueUpdateDraw(func() {
+				c.progress.Update(fmt.Sprintf("Copied %d of %d documents", progress.Copied, progress.Total))
+			})
+		})
+		c.App.QueueUpdateDraw(func() {
+			switch {
+			case ctx.Err() != nil:
+				c.progress.Finish(fmt.Sprintf("Copying cancelled after %d documents", copied))
+			case err != nil:
+				log.Error().Err(err).Msg("Error copying collection")
+				c.progress.Finish(fmt.Sprintf("Copying failed after %d documents: %s", copied, err))
+			default:
+				c.progress.Finish(fmt.Sprintf("Copied %d documents", copied))
+			}
+		})
+		cancel()
+	}()
+}
+
 func (c *Content) handleNextDocument(row, col int) *tcell.EventKey {
 	if c.currentView == JsonView {
 		c.table.MoveDownUntil(row, col, func(cell *tview.TableCell) bool {
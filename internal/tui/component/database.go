@@ -52,6 +52,8 @@ func (d *Database) init() error {
 	if err := d.DbTree.Init(d.App); err != nil {
 		return err
 	}
+	d.DbTree.SetLoadCollectionsFunc(d.loadCollections)
+	d.DbTree.SetReloadFunc(d.Render)
 
 	if err := d.filterBar.Init(d.App); err != nil {
 		return err
@@ -105,12 +107,12 @@ func (d *Database) Render() {
 	}
 	defer d.App.SetFocus(primitive)
 
-	if err := d.listDbsAndCollections(context.Background()); err != nil {
-		modal.ShowError(d.App.Pages, "Failed to list databases and collections", err)
+	if err := d.listDatabases(context.Background()); err != nil {
+		modal.ShowError(d.App.Pages, "Failed to list databases", err)
 		return
 	}
 
-	d.DbTree.Render(context.Background(), d.dbsWithColls, false)
+	d.DbTree.Render(context.Background(), d.GetDbsWithCollections(), false)
 
 	d.Flex.AddItem(d.DbTree, 0, 1, true)
 }
@@ -125,8 +127,10 @@ func (d *Database) filterBarHandler(ctx context.Context) {
 	d.filterBar.DoneFuncHandler(accceptFunc, rejectFunc)
 }
 
+// filter shows databases and collections matching the text, collections
+// are matched only in databases that were already expanded
 func (d *Database) filter(ctx context.Context, text string) {
-	dbsWitColls := d.dbsWithColls
+	dbsWitColls := d.GetDbsWithCollections()
 	expand := true
 	filtered := []mongo.DBsWithCollections{}
 	if text == "" {
@@ -148,6 +152,7 @@ func (d *Database) filter(ctx context.Context, text string) {
 				filteredDB := mongo.DBsWithCollections{
 					DB:          db.DB,
 					Collections: matchedCollections,
+					Loaded:      db.Loaded,
 				}
 				if matchedDB {
 					filteredDB.Collections = db.Collections
@@ -164,16 +169,65 @@ func (d *Database) filter(ctx context.Context, text string) {
 	d.App.SetFocus(d.DbTree)
 }
 
-func (d *Database) listDbsAndCollections(ctx context.Context) error {
-	dbsWitColls, err := d.Dao.ListDbsWithCollections(ctx, "")
+// listDatabases lists only names of databases, their collections
+// are loaded when the database is expanded
+func (d *Database) listDatabases(ctx context.Context) error {
+	dbs, err := d.Dao.ListDatabases(ctx, "")
 	if err != nil {
 		return err
 	}
-	d.dbsWithColls = dbsWitColls
+
+	dbsWithColls := make([]mongo.DBsWithCollections, 0, len(dbs))
+	for _, db := range dbs {
+		dbsWithColls = append(dbsWithColls, mongo.DBsWithCollections{DB: db})
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.dbsWithColls = dbsWithColls
 
 	return nil
 }
 
+// loadCollections lists collections of the database and stores them
+// with the database, so they can be filtered and completed
+func (d *Database) loadCollections(ctx context.Context, db string) ([]string, error) {
+	colls, err := d.Dao.ListCollections(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.dbsWithColls {
+		if d.dbsWithColls[i].DB == db {
+			d.dbsWithColls[i].Collections = colls
+			d.dbsWithColls[i].Loaded = true
+		}
+	}
+
+	return colls, nil
+}
+
+// GetDbsWithCollections returns databases loaded during the last render,
+// collections are set only for databases that were already expanded
+func (d *Database) GetDbsWithCollections() []mongo.DBsWithCollections {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]mongo.DBsWithCollections{}, d.dbsWithColls...)
+}
+
+// GetCollections returns collections of the database, loading them
+// if the database was not expanded yet
+func (d *Database) GetCollections(ctx context.Context, db string) ([]string, error) {
+	for _, dbWithColls := range d.GetDbsWithCollections() {
+		if dbWithColls.DB == db && dbWithColls.Loaded {
+			return dbWithColls.Collections, nil
+		}
+	}
+	return d.loadCollections(ctx, db)
+}
+
 func (d *Database) SetSelectFunc(f func(ctx context.Context, db string, coll string) error) {
 	d.DbTree.SetSelectFunc(f)
 }
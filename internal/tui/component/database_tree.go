@@ -3,6 +3,7 @@ package component
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -21,25 +22,62 @@ const (
 	ConfirmModalView      = "ConfirmModal"
 	DatabaseTreeComponent = "DatabaseTree"
 	DatabaseDeleteModal   = "DatabaseDeleteModal"
+	DatabaseFormModal     = "DatabaseFormModal"
+	DatabaseConfirmModal  = "DatabaseConfirmModal"
+
+	// countSuffixStart starts the document count shown after the collection name
+	countSuffixStart = " [::d]("
+	// maxConcurrentCounts limits number of count commands sent at once
+	maxConcurrentCounts = 4
 )
 
 type DatabaseTree struct {
 	*core.BaseElement
 	*core.TreeView
 
-	addModal    *primitives.InputModal
-	deleteModal *modal.Delete
-	style       *config.DatabasesStyle
+	addModal        *primitives.InputModal
+	deleteModal     *modal.Delete
+	schemaModal     *modal.Schema
+	statsModal      *modal.Stats
+	formModal       *modal.Form
+	confirmModal    *modal.Confirm
+	validationModal *modal.Validation
+	style           *config.DatabasesStyle
+
+	// counts caches document counts of collections by db.coll key,
+	// -1 means that the count is being fetched
+	showCounts bool
+	counts     map[string]int64
+	// countSlots limits number of counts fetched at once to maxConcurrentCounts
+	countSlots chan struct{}
+
+	nodeSelectFunc      func(ctx context.Context, db string, coll string) error
+	loadCollectionsFunc func(ctx context.Context, db string) ([]string, error)
+	reloadFunc          func()
+	showProfilerFunc    func(db string)
+	showUsersFunc       func(db string)
+}
 
-	nodeSelectFunc func(ctx context.Context, db string, coll string) error
+// dbReference is the reference of a database node, collections
+// are added to the node when it's expanded for the first time
+type dbReference struct {
+	name   string
+	loaded bool
 }
 
 func NewDatabaseTree() *DatabaseTree {
 	d := &DatabaseTree{
-		BaseElement: core.NewBaseElement(),
-		TreeView:    core.NewTreeView(),
-		addModal:    primitives.NewInputModal(),
-		deleteModal: modal.NewDeleteModal(DatabaseDeleteModal),
+		BaseElement:     core.NewBaseElement(),
+		TreeView:        core.NewTreeView(),
+		addModal:        primitives.NewInputModal(),
+		deleteModal:     modal.NewDeleteModal(DatabaseDeleteModal),
+		schemaModal:     modal.NewSchemaModal(),
+		statsModal:      modal.NewStatsModal(),
+		formModal:       modal.NewFormModal(DatabaseFormModal),
+		confirmModal:    modal.NewConfirmModal(DatabaseConfirmModal),
+		validationModal: modal.NewValidationModal(),
+		counts:          map[string]int64{},
+		countSlots:      make(chan struct{}, maxConcurrentCounts),
 	}
 
 	d.SetIdentifier(DatabaseTreeComponent)
@@ -61,6 +99,21 @@ func (t *DatabaseTree) init() error {
 	if err := t.deleteModal.Init(t.App); err != nil {
 		return err
 	}
+	if err := t.schemaModal.Init(t.App); err != nil {
+		return err
+	}
+	if err := t.statsModal.Init(t.App); err != nil {
+		return err
+	}
+	if err := t.formModal.Init(t.App); err != nil {
+		return err
+	}
+	if err := t.confirmModal.Init(t.App); err != nil {
+		return err
+	}
+	if err := t.validationModal.Init(t.App); err != nil {
+		return err
+	}
 
 	t.handleEvents()
 
@@ -106,17 +159,87 @@ func (t *DatabaseTree) setKeybindings(ctx context.Context) {
 		case k.Contains(k.Database.DeleteCollection, event.Name()):
 			t.showDeleteCollectionModal(ctx)
 			return nil
+		case k.Contains(k.Database.ShowSchema, event.Name()):
+			t.showSchemaModal(ctx)
+			return nil
+		case k.Contains(k.Database.ShowStats, event.Name()):
+			t.showStatsModal(ctx)
+			return nil
+		case k.Contains(k.Database.ToggleCounts, event.Name()):
+			t.toggleCounts()
+			return nil
+		case k.Contains(k.Database.RefreshNode, event.Name()):
+			t.refreshNode(ctx)
+			return nil
+		case k.Contains(k.Database.CreateCollection, event.Name()):
+			t.showCreateCollectionForm(ctx)
+			return nil
+		case k.Contains(k.Database.RenameCollection, event.Name()):
+			t.showRenameCollectionForm(ctx)
+			return nil
+		case k.Contains(k.Database.ConvertToCapped, event.Name()):
+			t.showConvertToCappedForm(ctx)
+			return nil
+		case k.Contains(k.Database.CreateView, event.Name()):
+			t.showCreateViewForm(ctx)
+			return nil
+		case k.Contains(k.Database.CreateDatabase, event.Name()):
+			t.showCreateDatabaseForm(ctx)
+			return nil
+		case k.Contains(k.Database.DropDatabase, event.Name()):
+			t.showDropDatabaseForm(ctx)
+			return nil
+		case k.Contains(k.Database.ShowProfiler, event.Name()):
+			if dbNode := t.getParentNode(); dbNode != nil && t.showProfilerFunc != nil {
+				t.showProfilerFunc(dbNode.GetReference().(*dbReference).name)
+			}
+			return nil
+		case k.Contains(k.Database.ShowValidation, event.Name()):
+			t.showValidationModal(ctx)
+			return nil
+		case k.Contains(k.Database.ShowUsers, event.Name()):
+			if dbNode := t.getParentNode(); dbNode != nil && t.showUsersFunc != nil {
+				t.showUsersFunc(dbNode.GetReference().(*dbReference).name)
+			}
+			return nil
 		}
 		return event
 	})
 }
 
+// expandAllNodes expands all databases, collections of databases that are
+// not loaded yet are listed in the background one database at a time,
+// so the tree stays responsive on servers with many databases
 func (t *DatabaseTree) expandAllNodes(closedSymbol, openSymbol string) {
+	notLoaded := []*tview.TreeNode{}
+	for _, dbNode := range t.GetRoot().GetChildren() {
+		if ref, ok := dbNode.GetReference().(*dbReference); ok && !ref.loaded {
+			loadingNode := tview.NewTreeNode("Loading collections...")
+			loadingNode.SetColor(t.style.LeafTextColor.Color())
+			loadingNode.SetSelectable(false)
+			dbNode.ClearChildren()
+			dbNode.AddChild(loadingNode)
+			notLoaded = append(notLoaded, dbNode)
+			continue
+		}
+		t.loadCounts(dbNode)
+	}
 	t.GetRoot().ExpandAll()
 	t.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
 		t.setNodeSymbol(node, closedSymbol, openSymbol)
 		return true
 	})
+
+	go func() {
+		for _, dbNode := range notLoaded {
+			ctx := context.Background()
+			colls, err := t.loadCollectionsFunc(ctx, dbNode.GetReference().(*dbReference).name)
+			t.App.QueueUpdateDraw(func() {
+				t.setCollections(ctx, dbNode, colls, err)
+				t.loadCounts(dbNode)
+			})
+		}
+	}()
 }
 
 func (t *DatabaseTree) collapseAllNodes(openSymbol, closedSymbol string) {
@@ -150,9 +273,13 @@ func (t *DatabaseTree) Render(ctx context.Context, dbsWitColls []mongo.DBsWithCo
 	}
 
 	for _, item := range dbsWitColls {
-		parent := t.dbNode(item.DB)
+		parent := t.dbNode(ctx, item.DB)
 		rootNode.AddChild(parent)
+		if !item.Loaded {
+			continue
+		}
 
+		parent.GetReference().(*dbReference).loaded = true
 		for _, child := range item.Collections {
 			t.addChildNode(ctx, parent, child, false)
 		}
@@ -161,15 +288,26 @@ func (t *DatabaseTree) Render(ctx context.Context, dbsWitColls []mongo.DBsWithCo
 	t.SetCurrentNode(rootNode.GetChildren()[0])
 	if expand {
 		t.GetRoot().ExpandAll()
+		for _, dbNode := range rootNode.GetChildren() {
+			if ref, ok := dbNode.GetReference().(*dbReference); ok && !ref.loaded {
+				dbNode.SetExpanded(false)
+			}
+		}
+	}
+	t.refreshCounts()
+	for _, dbNode := range rootNode.GetChildren() {
+		t.loadCounts(dbNode)
 	}
 }
 
 func (t *DatabaseTree) RefreshStyle() {
 	t.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
-		if parent != nil {
-			t.updateNodeSymbol(parent)
+		switch node.GetReference().(type) {
+		case *dbReference:
+			t.updateNodeSymbol(node)
+		case *tview.TreeNode:
+			t.updateLeafSymbol(node)
 		}
-		t.updateLeafSymbol(node)
 		return true
 	})
 }
@@ -232,6 +370,495 @@ func (t *DatabaseTree) showDeleteCollectionModal(ctx context.Context) error {
 	return nil
 }
 
+// showSchemaModal analyzes sampled documents of the selected collection
+func (t *DatabaseTree) showSchemaModal(ctx context.Context) {
+	if t.GetCurrentNode() == nil || t.GetCurrentNode().GetLevel() < 2 {
+		modal.ShowInfo(t.App.Pages, "Select a collection to analyze its schema")
+		return
+	}
+	parent := t.GetCurrentNode().GetReference().(*tview.TreeNode)
+	db, coll := t.removeSymbols(parent.GetText(), t.GetCurrentNode().GetText())
+
+	documents, err := t.Dao.SampleDocuments(ctx, db, coll, t.App.GetConfig().Schema.SampleSize)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error sampling documents", err)
+		return
+	}
+	t.schemaModal.Render(db, coll, mongo.AnalyzeSchema(documents))
+}
+
+// showValidationModal shows validation rules of the selected collection
+func (t *DatabaseTree) showValidationModal(ctx context.Context) {
+	node := t.GetCurrentNode()
+	if node == nil {
+		return
+	}
+	parent, ok := node.GetReference().(*tview.TreeNode)
+	if !ok {
+		modal.ShowInfo(t.App.Pages, "Select a collection to show its validation rules")
+		return
+	}
+	db, coll := t.removeSymbols(parent.GetText(), node.GetText())
+	t.renderValidation(ctx, db, coll)
+}
+
+func (t *DatabaseTree) renderValidation(ctx context.Context, db, coll string) {
+	validation, err := t.Dao.GetCollectionValidation(ctx, db, coll)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error getting validation rules", err)
+		return
+	}
+	err = t.validationModal.Render(db, coll, validation, func(buttonLabel string) {
+		switch buttonLabel {
+		case modal.ValidationEditButton:
+			t.editValidation(ctx, db, coll, validation)
+		case modal.ValidationValidateButton:
+			t.validateCollection(ctx, db, coll)
+		}
+	})
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error showing validation rules", err)
+	}
+}
+
+// editValidation opens validation rules in the editor and, after a dry run
+// that counts documents not matching new rules, asks to apply them
+func (t *DatabaseTree) editValidation(ctx context.Context, db, coll string, validation *mongo.CollectionValidation) {
+	rules, err := validation.ToJson()
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error editing validation rules", err)
+		return
+	}
+	edited, err := openEditor(t.App, rules)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error editing validation rules", err)
+		return
+	}
+	if edited == "" {
+		return
+	}
+	newValidation, err := mongo.ParseCollectionValidation(edited)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Invalid validation rules", err)
+		return
+	}
+
+	total, invalid, err := t.Dao.CountInvalidDocuments(ctx, db, coll, newValidation.Validator)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error checking documents against new rules", err)
+		return
+	}
+	content := fmt.Sprintf("%d of %d documents of %s.%s don't match new rules.\nValidation level: %s, action: %s\n\nApply new rules?",
+		invalid, total, db, coll, newValidation.Level, newValidation.Action)
+	t.confirmModal.Render("Apply validation rules", content, []string{"Apply", "Cancel"}, func(buttonLabel string) {
+		if buttonLabel != "Apply" {
+			return
+		}
+		if err := t.Dao.SetCollectionValidation(ctx, db, coll, newValidation); err != nil {
+			modal.ShowError(t.App.Pages, "Error applying validation rules", err)
+			return
+		}
+		t.renderValidation(ctx, db, coll)
+	})
+}
+
+// validateCollection runs the validate command on the collection
+func (t *DatabaseTree) validateCollection(ctx context.Context, db, coll string) {
+	result, err := t.Dao.ValidateCollection(ctx, db, coll)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error validating collection", err)
+		return
+	}
+	message := fmt.Sprintf("Collection %s.%s valid: %t\nRecords: %d, invalid documents: %d", db, coll,
+		result.Valid, result.Records, result.InvalidDocuments)
+	for _, warning := range result.Warnings {
+		message += "\nWarning: " + warning
+	}
+	for _, e := range result.Errors {
+		message += "\nError: " + e
+	}
+	modal.ShowInfo(t.App.Pages, message)
+}
+
+// showStatsModal shows statistics of the selected database or collection
+func (t *DatabaseTree) showStatsModal(ctx context.Context) {
+	node := t.GetCurrentNode()
+	if node == nil || node.GetLevel() == 0 {
+		return
+	}
+	if node.GetLevel() == 1 {
+		db, _ := t.removeSymbols(node.GetText(), "")
+		stats, err := t.Dao.GetDatabaseStats(ctx, db)
+		if err != nil {
+			modal.ShowError(t.App.Pages, "Error getting database statistics", err)
+			return
+		}
+		t.statsModal.RenderDatabase(db, stats)
+		return
+	}
+
+	parent := node.GetReference().(*tview.TreeNode)
+	db, coll := t.removeSymbols(parent.GetText(), node.GetText())
+	stats, err := t.Dao.GetCollectionStats(ctx, db, coll)
+	if err != nil {
+		modal.ShowError(t.App.Pages, "Error getting collection statistics", err)
+		return
+	}
+	t.statsModal.RenderCollection(db, coll, stats)
+}
+
+// toggleCounts shows or hides document counts next to collection names,
+// counts are fetched again every time they are shown
+func (t *DatabaseTree) toggleCounts() {
+	t.showCounts = !t.showCounts
+	if t.showCounts {
+		t.counts = map[string]int64{}
+		for _, dbNode := range t.GetRoot().GetChildren() {
+			t.loadCounts(dbNode)
+		}
+	}
+	t.refreshCounts()
+}
+
+// loadCounts fetches counts of collections of the expanded database
+// in the background, already fetched counts are taken from the cache
+func (t *DatabaseTree) loadCounts(dbNode *tview.TreeNode) {
+	if !t.showCounts || !dbNode.IsExpanded() {
+		return
+	}
+	db, _ := t.removeSymbols(dbNode.GetText(), "")
+	for _, node := range dbNode.GetChildren() {
+		_, coll := t.removeSymbols("", node.GetText())
+		key := db + "." + coll
+		if _, ok := t.counts[key]; ok {
+			continue
+		}
+		t.counts[key] = -1
+		go func() {
+			t.countSlots <- struct{}{}
+			count, err := t.Dao.CountDocuments(context.Background(), db, coll)
+			<-t.countSlots
+			if err != nil {
+				log.Error().Err(err).Msgf("Error counting documents of %s", key)
+				// count is fetched again the next time the database is expanded
+				t.App.QueueUpdateDraw(func() {
+					delete(t.counts, key)
+				})
+				return
+			}
+			t.App.QueueUpdateDraw(func() {
+				t.counts[key] = count
+				t.refreshCounts()
+			})
+		}()
+	}
+}
+
+// refreshCounts updates texts of all collection nodes with cached counts
+func (t *DatabaseTree) refreshCounts() {
+	t.GetRoot().Walk(func(node, _ *tview.TreeNode) bool {
+		if parent, ok := node.GetReference().(*tview.TreeNode); ok {
+			db, coll := t.removeSymbols(parent.GetText(), node.GetText())
+			node.SetText(t.collText(db, coll))
+		}
+		return true
+	})
+}
+
+func (t *DatabaseTree) collText(db, coll string) string {
+	leafSymbol := config.SymbolWithColor(t.style.LeafSymbol, t.style.LeafSymbolColor)
+	text := fmt.Sprintf("%s %s", leafSymbol, coll)
+	if count, ok := t.counts[db+"."+coll]; t.showCounts && ok && count >= 0 {
+		text += fmt.Sprintf("%s%d)[::-]", countSuffixStart, count)
+	}
+	return text
+}
+
+// loadCollections replaces children of the database node with its collections,
+// if they cannot be listed, the error is shown as the only child of the node
+func (t *DatabaseTree) loadCollections(ctx context.Context, dbNode *tview.TreeNode) {
+	colls, err := t.loadCollectionsFunc(ctx, dbNode.GetReference().(*dbReference).name)
+	t.setCollections(ctx, dbNode, colls, err)
+}
+
+// setCollections replaces children of the database node with listed collections
+// or with the error if they could not be listed
+func (t *DatabaseTree) setCollections(ctx context.Context, dbNode *tview.TreeNode, colls []string, err error) {
+	ref := dbNode.GetReference().(*dbReference)
+	dbNode.ClearChildren()
+
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing collections of %s", ref.name)
+		errNode := tview.NewTreeNode(tview.Escape(fmt.Sprintf("Cannot list collections: %s", err)))
+		errNode.SetColor(t.style.LeafTextColor.Color())
+		errNode.SetSelectable(false)
+		dbNode.AddChild(errNode)
+		ref.loaded = false
+		return
+	}
+
+	ref.loaded = true
+	for _, coll := range colls {
+		t.addChildNode(ctx, dbNode, coll, false)
+	}
+}
+
+// refreshNode lists collections of the selected database again,
+// together with their document counts
+func (t *DatabaseTree) refreshNode(ctx context.Context) {
+	dbNode := t.getParentNode()
+	if dbNode == nil {
+		return
+	}
+	ref := dbNode.GetReference().(*dbReference)
+	for key := range t.counts {
+		if strings.HasPrefix(key, ref.name+".") {
+			delete(t.counts, key)
+		}
+	}
+
+	t.loadCollections(ctx, dbNode)
+	t.SetCurrentNode(dbNode)
+	t.refreshCounts()
+	t.loadCounts(dbNode)
+}
+
+// showCreateCollectionForm creates capped, time series or clustered
+// collection in the selected database
+func (t *DatabaseTree) showCreateCollectionForm(ctx context.Context) {
+	dbNode := t.getParentNode()
+	if dbNode == nil {
+		return
+	}
+	db := dbNode.GetReference().(*dbReference).name
+
+	t.formModal.Render("Create collection in "+db, "Create", func(form *tview.Form) {
+		form.AddInputField("Name", "", 40, nil, nil)
+		form.AddDropDown("Type", mongo.CollectionTypes, 0, nil)
+		form.AddInputField("Size (bytes)", "", 20, tview.InputFieldInteger, nil)
+		form.AddInputField("Max documents", "", 20, tview.InputFieldInteger, nil)
+		form.AddInputField("Time field", "", 40, nil, nil)
+		form.AddInputField("Meta field", "", 40, nil, nil)
+		form.AddDropDown("Granularity", []string{"", "seconds", "minutes", "hours"}, 0, nil)
+		form.AddInputField("Expire after seconds", "", 20, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) error {
+		coll := getFormText(form, "Name")
+		if coll == "" {
+			return fmt.Errorf("collection name is required")
+		}
+		opts := mongo.CollectionOptions{
+			TimeField: getFormText(form, "Time field"),
+			MetaField: getFormText(form, "Meta field"),
+		}
+		_, opts.Type = form.GetFormItemByLabel("Type").(*tview.DropDown).GetCurrentOption()
+		_, opts.Granularity = form.GetFormItemByLabel("Granularity").(*tview.DropDown).GetCurrentOption()
+		var err error
+		if opts.Size, err = getFormInt(form, "Size (bytes)"); err != nil {
+			return err
+		}
+		if opts.MaxDocuments, err = getFormInt(form, "Max documents"); err != nil {
+			return err
+		}
+		if opts.ExpireAfterSeconds, err = getFormInt(form, "Expire after seconds"); err != nil {
+			return err
+		}
+
+		if err := t.Dao.CreateCollection(ctx, db, coll, opts); err != nil {
+			return err
+		}
+		t.reloadDbNode(ctx, dbNode, coll)
+		return nil
+	})
+}
+
+// showRenameCollectionForm renames the selected collection
+func (t *DatabaseTree) showRenameCollectionForm(ctx context.Context) {
+	node := t.GetCurrentNode()
+	if node == nil {
+		return
+	}
+	parent, ok := node.GetReference().(*tview.TreeNode)
+	if !ok {
+		modal.ShowInfo(t.App.Pages, "Select a collection to rename")
+		return
+	}
+	db, coll := t.removeSymbols(parent.GetText(), node.GetText())
+
+	t.formModal.Render(fmt.Sprintf("Rename %s.%s", db, coll), "Rename", func(form *tview.Form) {
+		form.AddInputField("New name", coll, 40, nil, nil)
+	}, func(form *tview.Form) error {
+		newName := getFormText(form, "New name")
+		if newName == "" || newName == coll {
+			return fmt.Errorf("new name must be different from %s", coll)
+		}
+		if err := t.Dao.RenameCollection(ctx, db, coll, newName); err != nil {
+			return err
+		}
+		t.reloadDbNode(ctx, parent, newName)
+		return nil
+	})
+}
+
+// showConvertToCappedForm converts the selected collection to capped one
+func (t *DatabaseTree) showConvertToCappedForm(ctx context.Context) {
+	node := t.GetCurrentNode()
+	if node == nil {
+		return
+	}
+	parent, ok := node.GetReference().(*tview.TreeNode)
+	if !ok {
+		modal.ShowInfo(t.App.Pages, "Select a collection to convert")
+		return
+	}
+	db, coll := t.removeSymbols(parent.GetText(), node.GetText())
+
+	t.formModal.Render(fmt.Sprintf("Convert %s.%s to capped", db, coll), "Convert", func(form *tview.Form) {
+		form.AddInputField("Size (bytes)", "", 20, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) error {
+		size, err := getFormInt(form, "Size (bytes)")
+		if err != nil {
+			return err
+		}
+		if err := t.Dao.ConvertToCapped(ctx, db, coll, size); err != nil {
+			return err
+		}
+		t.reloadDbNode(ctx, parent, coll)
+		return nil
+	})
+}
+
+// showCreateViewForm creates a view in the selected database,
+// the selected collection is the default source of the view
+func (t *DatabaseTree) showCreateViewForm(ctx context.Context) {
+	dbNode := t.getParentNode()
+	if dbNode == nil {
+		return
+	}
+	db, source := t.removeSymbols(dbNode.GetText(), "")
+	if dbNode != t.GetCurrentNode() {
+		_, source = t.removeSymbols("", t.GetCurrentNode().GetText())
+	}
+
+	t.formModal.Render("Create view in "+db, "Create", func(form *tview.Form) {
+		form.AddInputField("Name", "", 40, nil, nil)
+		form.AddInputField("Source", source, 40, nil, nil)
+		form.AddInputField("Pipeline", "[]", 60, nil, nil)
+	}, func(form *tview.Form) error {
+		view, source := getFormText(form, "Name"), getFormText(form, "Source")
+		if view == "" || source == "" {
+			return fmt.Errorf("name and source of the view are required")
+		}
+		pipeline, err := mongo.ParsePipeline(getFormText(form, "Pipeline"))
+		if err != nil {
+			return err
+		}
+		if err := t.Dao.CreateView(ctx, db, view, source, pipeline); err != nil {
+			return err
+		}
+		t.reloadDbNode(ctx, dbNode, view)
+		return nil
+	})
+}
+
+// showCreateDatabaseForm creates a database with its first collection,
+// as databases without collections do not exist
+func (t *DatabaseTree) showCreateDatabaseForm(ctx context.Context) {
+	t.formModal.Render("Create database", "Create", func(form *tview.Form) {
+		form.AddInputField("Database", "", 40, nil, nil)
+		form.AddInputField("First collection", "", 40, nil, nil)
+	}, func(form *tview.Form) error {
+		db, coll := getFormText(form, "Database"), getFormText(form, "First collection")
+		if db == "" || coll == "" {
+			return fmt.Errorf("database and collection names are required")
+		}
+		if err := t.Dao.AddCollection(ctx, db, coll); err != nil {
+			return err
+		}
+		t.reloadFunc()
+		for _, dbNode := range t.GetRoot().GetChildren() {
+			if ref, ok := dbNode.GetReference().(*dbReference); ok && ref.name == db {
+				t.toggleDbNode(ctx, dbNode)
+				t.SetCurrentNode(dbNode)
+			}
+		}
+		return nil
+	})
+}
+
+// showDropDatabaseForm drops the selected database, its name has
+// to be typed to confirm it
+func (t *DatabaseTree) showDropDatabaseForm(ctx context.Context) {
+	dbNode := t.getParentNode()
+	if dbNode == nil {
+		return
+	}
+	db := dbNode.GetReference().(*dbReference).name
+
+	t.formModal.Render("Drop database "+db, "Drop", func(form *tview.Form) {
+		form.AddTextView("Warning", "All collections of the database will be deleted", 50, 1, true, false)
+		form.AddInputField("Type database name", "", 40, nil, nil)
+	}, func(form *tview.Form) error {
+		if getFormText(form, "Type database name") != db {
+			return fmt.Errorf("typed name does not match %s", db)
+		}
+		if err := t.Dao.DropDatabase(ctx, db); err != nil {
+			return err
+		}
+		t.reloadFunc()
+		return nil
+	})
+}
+
+// reloadDbNode lists collections of the database again after they were
+// changed, expands it and selects the collection
+func (t *DatabaseTree) reloadDbNode(ctx context.Context, dbNode *tview.TreeNode, coll string) {
+	t.loadCollections(ctx, dbNode)
+	if !dbNode.IsExpanded() {
+		t.toggleDbNode(ctx, dbNode)
+	}
+	t.SetCurrentNode(dbNode)
+	for _, node := range dbNode.GetChildren() {
+		if _, name := t.removeSymbols("", node.GetText()); name == coll {
+			t.SetCurrentNode(node)
+		}
+	}
+	t.refreshCounts()
+	t.loadCounts(dbNode)
+}
+
+func getFormText(form *tview.Form, label string) string {
+	return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+}
+
+// getFormInt returns number typed in the field, 0 if it's empty
+func getFormInt(form *tview.Form, label string) (int64, error) {
+	text := getFormText(form, label)
+	if text == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", label)
+	}
+	return number, nil
+}
+
+func (t *DatabaseTree) SetReloadFunc(f func()) {
+	t.reloadFunc = f
+}
+
+func (t *DatabaseTree) SetShowProfilerFunc(f func(db string)) {
+	t.showProfilerFunc = f
+}
+
+func (t *DatabaseTree) SetShowUsersFunc(f func(db string)) {
+	t.showUsersFunc = f
+}
+
+func (t *DatabaseTree) SetLoadCollectionsFunc(f func(ctx context.Context, db string) ([]string, error)) {
+	t.loadCollectionsFunc = f
+}
+
 func (t *DatabaseTree) SetSelectFunc(f func(ctx context.Context, db string, coll string) error) {
 	t.nodeSelectFunc = f
 }
@@ -258,26 +885,39 @@ func (t *DatabaseTree) rootNode() *tview.TreeNode {
 	return r
 }
 
-func (t *DatabaseTree) dbNode(name string) *tview.TreeNode {
-	openNodeSymbol := config.SymbolWithColor(t.style.OpenNodeSymbol, t.style.NodeSymbolColor)
+func (t *DatabaseTree) dbNode(ctx context.Context, name string) *tview.TreeNode {
 	closedNodeSymbol := config.SymbolWithColor(t.style.ClosedNodeSymbol, t.style.NodeSymbolColor)
 	r := tview.NewTreeNode(fmt.Sprintf("%s %s", closedNodeSymbol, name))
 	r.SetColor(t.style.NodeTextColor.Color())
 	r.SetSelectable(true)
 	r.SetExpanded(false)
+	r.SetReference(&dbReference{name: name})
 
 	r.SetSelectedFunc(func() {
-		if r.IsExpanded() {
-			r.SetText(fmt.Sprintf("%s %s", closedNodeSymbol, name))
-		} else {
-			r.SetText(fmt.Sprintf("%s %s", openNodeSymbol, name))
-		}
-		r.SetExpanded(!r.IsExpanded())
+		t.toggleDbNode(ctx, r)
 	})
 
 	return r
 }
 
+// toggleDbNode expands or collapses the database node, collections
+// are loaded when the node is expanded for the first time
+func (t *DatabaseTree) toggleDbNode(ctx context.Context, node *tview.TreeNode) {
+	openNodeSymbol := config.SymbolWithColor(t.style.OpenNodeSymbol, t.style.NodeSymbolColor)
+	closedNodeSymbol := config.SymbolWithColor(t.style.ClosedNodeSymbol, t.style.NodeSymbolColor)
+	ref := node.GetReference().(*dbReference)
+	if node.IsExpanded() {
+		node.SetText(fmt.Sprintf("%s %s", closedNodeSymbol, ref.name))
+	} else {
+		if !ref.loaded {
+			t.loadCollections(ctx, node)
+		}
+		node.SetText(fmt.Sprintf("%s %s", openNodeSymbol, ref.name))
+	}
+	node.SetExpanded(!node.IsExpanded())
+	t.loadCounts(node)
+}
+
 func (t *DatabaseTree) collNode(name string) *tview.TreeNode {
 	leafSymbol := config.SymbolWithColor(t.style.LeafSymbol, t.style.LeafSymbolColor)
 	ch := tview.NewTreeNode(fmt.Sprintf("%s %s", leafSymbol, name))
@@ -302,6 +942,7 @@ func (t *DatabaseTree) removeSymbols(db, coll string) (string, string) {
 		db = strings.ReplaceAll(db, symbol, "")
 		coll = strings.ReplaceAll(coll, symbol, "")
 	}
+	coll, _, _ = strings.Cut(coll, countSuffixStart)
 
 	return strings.TrimSpace(db), strings.TrimSpace(coll)
 }
@@ -312,6 +953,9 @@ func (t *DatabaseTree) setNodeSymbol(node *tview.TreeNode, oldSymbol, newSymbol
 }
 
 func (t *DatabaseTree) getParentNode() *tview.TreeNode {
+	if t.GetCurrentNode() == nil {
+		return nil
+	}
 	level := t.GetCurrentNode().GetLevel()
 	if level == 0 {
 		return nil
@@ -373,32 +1017,20 @@ func (t *DatabaseTree) updateNodeSymbol(node *tview.TreeNode) {
 	node.SetColor(t.style.NodeTextColor.Color())
 	openNodeSymbol := config.SymbolWithColor(t.style.OpenNodeSymbol, t.style.NodeSymbolColor)
 	closedNodeSymbol := config.SymbolWithColor(t.style.ClosedNodeSymbol, t.style.NodeSymbolColor)
-	currText := strings.Split(node.GetText(), " ")
-	if len(currText) < 2 {
-		return
-	}
+	name := node.GetReference().(*dbReference).name
 	if node.IsExpanded() {
-		node.SetText(fmt.Sprintf("%s %s", openNodeSymbol, currText[1]))
+		node.SetText(fmt.Sprintf("%s %s", openNodeSymbol, name))
 	} else {
-		node.SetText(fmt.Sprintf("%s %s", closedNodeSymbol, currText[1]))
+		node.SetText(fmt.Sprintf("%s %s", closedNodeSymbol, name))
 	}
-
-	node.SetSelectedFunc(func() {
-		if node.IsExpanded() {
-			node.SetText(fmt.Sprintf("%s %s", closedNodeSymbol, currText[1]))
-		} else {
-			node.SetText(fmt.Sprintf("%s %s", openNodeSymbol, currText[1]))
-		}
-		node.SetExpanded(!node.IsExpanded())
-	})
 }
 
 func (t *DatabaseTree) updateLeafSymbol(node *tview.TreeNode) {
 	node.SetColor(t.style.LeafTextColor.Color())
-	leafSymbol := config.SymbolWithColor(t.style.LeafSymbol, t.style.LeafSymbolColor)
-	currText := strings.Split(node.GetText(), " ")
-	if len(currText) < 2 {
-		return
-	}
-	node.SetText(fmt.Sprintf("%s %s", leafSymbol, currText[1]))
+	parent := node.GetReference().(*tview.TreeNode)
+	db, _ := t.removeSymbols(parent.GetText(), "")
+	// leaf symbol of the previous style can't be removed, so name starts after it
+	_, coll, _ := strings.Cut(node.GetText(), " ")
+	coll, _, _ = strings.Cut(coll, countSuffixStart)
+	node.SetText(t.collText(db, coll))
 }
@@ -1,42 +1,58 @@
 package component
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/kopecmaciej/vi-mongo/internal/mongo"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
-	DocModifierView = "DocModifier"
+	DocModifierView   = "DocModifier"
+	UpdateConfirmView = "UpdateConfirmModal"
 )
 
 // DocModifier is a view that allows editing JSON documents
 type DocModifier struct {
 	*core.BaseElement
+
+	confirmModal *modal.Confirm
 }
 
 func NewDocModifier() *DocModifier {
-	return &DocModifier{
-		BaseElement: core.NewBaseElement(),
+	d := &DocModifier{
+		BaseElement:  core.NewBaseElement(),
+		confirmModal: modal.NewConfirmModal(UpdateConfirmView),
 	}
+
+	d.SetAfterInitFunc(d.init)
+
+	return d
+}
+
+func (d *DocModifier) init() error {
+	return d.confirmModal.Init(d.App)
 }
 
 func (d *DocModifier) Insert(ctx context.Context, db, coll string) (primitive.ObjectID, error) {
-	createdDoc, err := d.openEditor("{}")
+	createdDoc, err := d.openValidatedEditor(ctx, db, coll, "{}", nil, func(edited string) (primitive.M, error) {
+		var document map[string]interface{}
+		err := json.Unmarshal([]byte(edited), &document)
+		return document, err
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Error opening editor")
-		return primitive.NilObjectID, nil
+		return primitive.NilObjectID, fmt.Errorf("error editing document: %v", err)
 	}
-	if strings.ReplaceAll(createdDoc, " ", "") == "{}" {
+	if util.IsJsonEmpty(util.CleanJsonWhitespaces(createdDoc)) {
 		log.Debug().Msgf("No document created")
 		return primitive.NilObjectID, nil
 	}
@@ -51,6 +67,7 @@ func (d *DocModifier) Insert(ctx context.Context, db, coll string) (primitive.Ob
 	if err != nil {
 		return primitive.NilObjectID, fmt.Errorf("error inserting document: %v", err)
 	}
+	d.recordInsert(db, coll, rawId, document)
 
 	id, ok := rawId.(primitive.ObjectID)
 	if !ok {
@@ -60,24 +77,323 @@ func (d *DocModifier) Insert(ctx context.Context, db, coll string) (primitive.Ob
 	return id, nil
 }
 
-// Edit opens the editor with the document and saves it if it was changed
-func (d *DocModifier) Edit(ctx context.Context, db, coll string, _id interface{}, jsonDoc string) (string, error) {
-	updatedDocument, err := d.openEditor(jsonDoc)
+// Edit opens the editor with the document, shows the changes that will be applied
+// and saves them after confirmation. onSaved is called with the document
+// that is stored in the database after the operation.
+func (d *DocModifier) Edit(ctx context.Context, db, coll string, _id interface{}, jsonDoc string, onSaved func(updatedDoc string)) error {
+	return d.edit(ctx, db, coll, _id, jsonDoc, jsonDoc, onSaved)
+}
+
+// edit opens the editor with editorDoc and saves the differences between
+// the edited document and originalDoc
+func (d *DocModifier) edit(ctx context.Context, db, coll string, _id interface{}, originalDoc, editorDoc string, onSaved func(updatedDoc string)) error {
+	existing, err := mongo.ParseJsonToBson(originalDoc)
 	if err != nil {
-		return "", fmt.Errorf("error editing document: %v", err)
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	updatedDocument, err := d.openValidatedEditor(ctx, db, coll, editorDoc, existing, mongo.ParseJsonToBson)
+	if err != nil {
+		return fmt.Errorf("error editing document: %v", err)
 	}
 
-	if strings.ReplaceAll(updatedDocument, " ", "") == strings.ReplaceAll(jsonDoc, " ", "") {
+	if updatedDocument == "" {
+		log.Debug().Msgf("Document editing cancelled")
+		return nil
+	}
+	if strings.ReplaceAll(updatedDocument, " ", "") == strings.ReplaceAll(originalDoc, " ", "") {
 		log.Debug().Msgf("Edited JSON is the same as original")
-		return "", nil
+		return nil
+	}
+
+	parsedOriginalDoc, parsedDoc, err := d.parseDocuments(originalDoc, updatedDocument)
+	if err != nil {
+		return err
+	}
+
+	update := mongo.BuildUpdate(parsedOriginalDoc, parsedDoc)
+	if len(update) == 0 {
+		log.Debug().Msgf("No changes in edited document")
+		return nil
+	}
+
+	changes, err := formatUpdate(update)
+	if err != nil {
+		return fmt.Errorf("error formatting changes: %v", err)
+	}
+
+	d.confirmModal.Render("Save changes?", changes, []string{"Save", "Cancel"}, func(buttonLabel string) {
+		if buttonLabel != "Save" {
+			return
+		}
+		d.saveChanges(ctx, db, coll, _id, parsedOriginalDoc, parsedDoc, updatedDocument, onSaved)
+	})
+
+	return nil
+}
+
+// saveChanges stores the changes and handles the case when the document
+// was modified in the meantime
+func (d *DocModifier) saveChanges(ctx context.Context, db, coll string, _id interface{}, originalDoc, updatedDoc primitive.M, updatedJson string, onSaved func(updatedDoc string)) {
+	err := d.validate(ctx, db, coll, withId(_id, originalDoc), withId(_id, updatedDoc))
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error saving document", err)
+		return
+	}
+
+	stored, err := d.Dao.UpdateDocument(ctx, db, coll, _id, originalDoc, updatedDoc)
+	if errors.Is(err, mongo.ErrDocumentConflict) {
+		d.handleConflict(ctx, db, coll, _id, originalDoc, updatedDoc, updatedJson, onSaved)
+		return
+	}
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error saving document", err)
+		return
+	}
+	// stored document is recorded, as it has incremented version if versioning is on
+	if stored != nil {
+		d.App.GetJournal().Record(mongo.JournalEntry{
+			Type:   mongo.UpdateOperation,
+			Db:     db,
+			Coll:   coll,
+			Id:     _id,
+			Before: withId(_id, originalDoc),
+			After:  stored,
+		})
 	}
+	onSaved(updatedJson)
+}
+
+// schemaViolationError is returned when the edited document doesn't match
+// $jsonSchema of the collection and the editor was closed without changes
+type schemaViolationError struct {
+	db, coll   string
+	violations []mongo.SchemaViolation
+}
 
-	err = d.updateDocument(ctx, db, coll, _id, jsonDoc, updatedDocument)
+func (e *schemaViolationError) Error() string {
+	return fmt.Sprintf("document doesn't match the schema of %s.%s:\n%s", e.db, e.coll,
+		strings.Join(formatViolations(e.violations), "\n"))
+}
+
+// getJsonSchema returns $jsonSchema of the collection, nil is returned
+// if it can't be read, as the document is validated by the server anyway
+func (d *DocModifier) getJsonSchema(ctx context.Context, db, coll string) *mongo.JsonSchema {
+	schema, err := d.Dao.GetJsonSchema(ctx, db, coll)
 	if err != nil {
-		return "", fmt.Errorf("error saving document: %v", err)
+		log.Error().Err(err).Msgf("Error getting schema of %s.%s", db, coll)
+		return nil
+	}
+	return schema
+}
+
+// validate checks that the document replacing the existing one matches
+// $jsonSchema of the collection, existing is nil for inserts
+func (d *DocModifier) validate(ctx context.Context, db, coll string, existing, document primitive.M) error {
+	violations := d.getJsonSchema(ctx, db, coll).Validate(existing, document)
+	if len(violations) > 0 {
+		return &schemaViolationError{db: db, coll: coll, violations: violations}
+	}
+	return nil
+}
+
+// openValidatedEditor opens the editor until the edited document can be
+// converted with toDocument and matches $jsonSchema of the collection, errors
+// are shown above the document when the editor is reopened. toDocument converts
+// edited JSON to the document that will be stored, existing is the document
+// it replaces or nil for inserts
+func (d *DocModifier) openValidatedEditor(ctx context.Context, db, coll, rawDocument string, existing primitive.M, toDocument func(edited string) (primitive.M, error)) (string, error) {
+	schema := d.getJsonSchema(ctx, db, coll)
+
+	var notes []string
+	for {
+		edited, err := openEditorWithNotes(d.App, rawDocument, notes)
+		if err != nil || edited == "" {
+			return edited, err
+		}
+		unchanged := compactJson(edited) == compactJson(rawDocument)
+		if unchanged && notes == nil {
+			return edited, nil
+		}
+
+		document, err := toDocument(edited)
+		if err != nil {
+			// e.g. valid JSON with invalid extended JSON like {"$oid": "zzz"}
+			if unchanged {
+				return "", fmt.Errorf("document is not valid: %w", err)
+			}
+			notes = append([]string{"Document is not valid, close the editor without changes to cancel:"},
+				formatError(err)...)
+			rawDocument = edited
+			continue
+		}
+		violations := schema.Validate(existing, document)
+		if len(violations) == 0 {
+			return edited, nil
+		}
+		if unchanged {
+			return "", &schemaViolationError{db: db, coll: coll, violations: violations}
+		}
+
+		notes = append([]string{fmt.Sprintf("Document doesn't match the schema of %s.%s, close the editor without changes to cancel:", db, coll)},
+			formatViolations(violations)...)
+		rawDocument = edited
+	}
+}
+
+// formatError returns the error as indented note lines
+func formatError(err error) []string {
+	lines := strings.Split(err.Error(), "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
 	}
+	return lines
+}
 
-	return updatedDocument, nil
+func formatViolations(violations []mongo.SchemaViolation) []string {
+	formatted := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		formatted = append(formatted, "  - "+violation.String())
+	}
+	return formatted
+}
+
+// UpdateField sets a single field of the document, key can be a dotted path
+// of nested field. Document is the current version of the whole document
+// used as a pre-image
+func (d *DocModifier) UpdateField(ctx context.Context, db, coll string, document primitive.M, key string, value interface{}) (primitive.M, error) {
+	_id := document["_id"]
+	original := primitive.M{}
+	if oldValue, ok := util.GetValueByPath(document, key); ok {
+		original[key] = oldValue
+	}
+	// version has to be checked and incremented like in the full edit
+	if versionField := d.Dao.Config.VersionField; versionField != "" {
+		if version, ok := document[versionField]; ok {
+			original[versionField] = version
+		}
+	}
+
+	updated, err := util.SetValueByPath(document, key, value)
+	if err != nil {
+		return nil, err
+	}
+	err = d.validate(ctx, db, coll, document, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := d.Dao.UpdateDocument(ctx, db, coll, _id, original, primitive.M{key: value})
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return updated, nil
+	}
+
+	d.App.GetJournal().Record(mongo.JournalEntry{
+		Type:   mongo.UpdateOperation,
+		Db:     db,
+		Coll:   coll,
+		Id:     _id,
+		Before: document,
+		After:  stored,
+	})
+
+	return stored, nil
+}
+
+// recordInsert adds inserted document to the journal so it can be undone
+func (d *DocModifier) recordInsert(db, coll string, _id interface{}, document primitive.M) {
+	d.App.GetJournal().Record(mongo.JournalEntry{
+		Type:  mongo.InsertOperation,
+		Db:    db,
+		Coll:  coll,
+		Id:    _id,
+		After: withId(_id, document),
+	})
+}
+
+// handleConflict lets the user decide what to do with the document that was
+// changed by someone else: reload it, overwrite it or merge both versions
+func (d *DocModifier) handleConflict(ctx context.Context, db, coll string, _id interface{}, originalDoc, updatedDoc primitive.M, updatedJson string, onSaved func(updatedDoc string)) {
+	currentJson, currentDoc, err := d.getCurrentDocument(ctx, db, coll, _id)
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error getting current document", err)
+		return
+	}
+
+	changes, err := formatUpdate(mongo.BuildUpdate(originalDoc, currentDoc))
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error formatting changes", err)
+		return
+	}
+
+	text := "Document was modified after it was loaded, changes made in the meantime:\n" + changes
+	d.confirmModal.Render("Conflict", text, []string{"Reload", "Overwrite", "Merge", "Cancel"}, func(buttonLabel string) {
+		switch buttonLabel {
+		case "Reload":
+			onSaved(currentJson)
+		case "Overwrite":
+			d.saveChanges(ctx, db, coll, _id, currentDoc, updatedDoc, updatedJson, onSaved)
+		case "Merge":
+			d.showMerge(ctx, db, coll, _id, originalDoc, updatedDoc, currentJson, currentDoc, onSaved)
+		}
+	})
+}
+
+// showMerge shows the result of three-way merge of the original document,
+// our changes and the current version from the database
+func (d *DocModifier) showMerge(ctx context.Context, db, coll string, _id interface{}, originalDoc, updatedDoc primitive.M, currentJson string, currentDoc primitive.M, onSaved func(updatedDoc string)) {
+	merged, conflicts := mongo.MergeDocuments(originalDoc, updatedDoc, currentDoc)
+
+	mergedJson, err := stringifyWithId(_id, merged)
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error merging documents", err)
+		return
+	}
+
+	text := "No conflicting fields, merged document:\n"
+	if len(conflicts) > 0 {
+		text = formatConflicts(conflicts) + "\nConflicting fields use your values, merged document:\n"
+	}
+	indented, err := mongo.IndentJson(mergedJson)
+	if err != nil {
+		modal.ShowError(d.App.Pages, "Error merging documents", err)
+		return
+	}
+	text += indented.String()
+
+	d.confirmModal.Render("Merge", text, []string{"Save", "Edit", "Cancel"}, func(buttonLabel string) {
+		switch buttonLabel {
+		case "Save":
+			d.saveChanges(ctx, db, coll, _id, currentDoc, merged, mergedJson, onSaved)
+		case "Edit":
+			err := d.edit(ctx, db, coll, _id, currentJson, mergedJson, onSaved)
+			if err != nil {
+				modal.ShowError(d.App.Pages, "Error editing document", err)
+			}
+		}
+	})
+}
+
+// getCurrentDocument returns the document as it's stored in the database,
+// both as JSON and parsed without _id field
+func (d *DocModifier) getCurrentDocument(ctx context.Context, db, coll string, _id interface{}) (string, primitive.M, error) {
+	current, err := d.Dao.GetDocument(ctx, db, coll, _id)
+	if err != nil {
+		return "", nil, err
+	}
+	currentJson, err := mongo.ParseBsonDocument(current)
+	if err != nil {
+		return "", nil, err
+	}
+	currentDoc, err := mongo.ParseJsonToBson(currentJson)
+	if err != nil {
+		return "", nil, err
+	}
+	delete(currentDoc, "_id")
+
+	return currentJson, currentDoc, nil
 }
 
 // Duplicate opens the editor with the document and saves it as a new document
@@ -87,7 +403,11 @@ func (d *DocModifier) Duplicate(ctx context.Context, db, coll string, rawDocumen
 		return primitive.NilObjectID, fmt.Errorf("error removing _id field: %v", err)
 	}
 
-	duplicateDoc, err := d.openEditor(replacedDoc)
+	duplicateDoc, err := d.openValidatedEditor(ctx, db, coll, replacedDoc, nil, func(edited string) (primitive.M, error) {
+		document, err := mongo.ParseJsonToBson(edited)
+		delete(document, "_id")
+		return document, err
+	})
 	if err != nil {
 		return primitive.NilObjectID, fmt.Errorf("error editing document: %v", err)
 	}
@@ -107,6 +427,7 @@ func (d *DocModifier) Duplicate(ctx context.Context, db, coll string, rawDocumen
 	if err != nil {
 		return primitive.NilObjectID, fmt.Errorf("error inserting document: %v", err)
 	}
+	d.recordInsert(db, coll, rawID, parsedDoc)
 
 	id, ok := rawID.(primitive.ObjectID)
 	if !ok {
@@ -116,104 +437,75 @@ func (d *DocModifier) Duplicate(ctx context.Context, db, coll string, rawDocumen
 	return id, nil
 }
 
-// updateDocument saves the document to the database
-func (d *DocModifier) updateDocument(ctx context.Context, db, coll string, _id interface{}, originalDoc, rawDocument string) error {
+// parseDocuments parses the original and updated documents
+// and removes _id fields from them, as _id is immutable
+func (d *DocModifier) parseDocuments(originalDoc, rawDocument string) (primitive.M, primitive.M, error) {
 	if rawDocument == "" {
-		return fmt.Errorf("document cannot be empty")
+		return nil, nil, fmt.Errorf("document cannot be empty")
 	}
 
 	parsedDoc, err := mongo.ParseJsonToBson(rawDocument)
 	if err != nil {
-		return fmt.Errorf("error parsing JSON: %v", err)
+		return nil, nil, fmt.Errorf("error parsing JSON: %v", err)
 	}
 
 	parsedOriginalDoc, err := mongo.ParseJsonToBson(originalDoc)
 	if err != nil {
-		return fmt.Errorf("error parsing JSON: %v", err)
+		return nil, nil, fmt.Errorf("error parsing JSON: %v", err)
 	}
 
 	delete(parsedDoc, "_id")
 	delete(parsedOriginalDoc, "_id")
-	err = d.Dao.UpdateDocument(ctx, db, coll, _id, parsedOriginalDoc, parsedDoc)
-	if err != nil {
-		log.Error().Msgf("error updating document: %v", err)
-		return nil
-	}
 
-	return nil
+	return parsedOriginalDoc, parsedDoc, nil
 }
 
-// openEditor opens the editor with the document and returns the edited document
-func (d *DocModifier) openEditor(rawDocument string) (string, error) {
-	prettyJsonBuffer, err := mongo.IndentJson(rawDocument)
+// formatUpdate returns indented extended JSON of the update operations
+func formatUpdate(update primitive.M) (string, error) {
+	extJson, err := bson.MarshalExtJSON(update, false, false)
 	if err != nil {
-		return "", fmt.Errorf("error indenting JSON: %v", err)
-	}
-
-	tmpFile, err := d.writeToTempFile(prettyJsonBuffer)
-	if err != nil {
-		return "", fmt.Errorf("error writing to temp file: %v", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	ed, err := d.App.GetConfig().GetEditorCmd()
-	if err != nil {
-		return "", fmt.Errorf("error getting editor command: %v", err)
+		return "", err
 	}
-	editor, err := exec.LookPath(ed)
+	indented, err := mongo.IndentJson(string(extJson))
 	if err != nil {
-		return "", fmt.Errorf("error looking for editor: %v", err)
+		return "", err
 	}
+	return indented.String(), nil
+}
 
-	updatedDocument := ""
-
-	d.App.Suspend(func() {
-		cmd := exec.Command(editor, tmpFile.Name())
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		err = cmd.Run()
-		if err != nil {
-			log.Error().Err(err).Msg("error running editor")
-			return
+// formatConflicts returns human readable list of conflicting fields
+func formatConflicts(conflicts []mongo.MergeConflict) string {
+	stringify := func(value interface{}) string {
+		if value == nil {
+			return "<missing>"
 		}
-
-		editedBytes, err := os.ReadFile(tmpFile.Name())
+		extJson, err := bson.MarshalExtJSON(primitive.M{"v": value}, false, false)
 		if err != nil {
-			log.Error().Err(err).Msg("error reading edited file")
-			return
+			return fmt.Sprintf("%v", value)
 		}
-		if !json.Valid(editedBytes) {
-			log.Error().Msg("Edited JSON is not valid")
-			return
-		}
-		updatedDocument = string(editedBytes)
-	})
-
-	return updatedDocument, nil
-}
-
-// writeToTempFile writes the JSON to a temp file and returns the file
-func (d *DocModifier) writeToTempFile(bufferJson bytes.Buffer) (*os.File, error) {
-	tmpFile, err := os.CreateTemp("", "doc-*.json")
-	if err != nil {
-		return nil, fmt.Errorf("error creating temp file: %v", err)
+		return strings.TrimSuffix(strings.TrimPrefix(string(extJson), `{"v":`), "}")
 	}
 
-	_, err = tmpFile.Write(bufferJson.Bytes())
-	if err != nil {
-		err = os.Remove(tmpFile.Name())
-		if err != nil {
-			return nil, fmt.Errorf("error removing temp file: %v", err)
-		}
-		return nil, fmt.Errorf("error writing to temp file: %v", err)
+	text := "Conflicting fields:\n"
+	for _, conflict := range conflicts {
+		text += fmt.Sprintf("  %s: original %s, yours %s, theirs %s\n",
+			conflict.Key, stringify(conflict.Base), stringify(conflict.Mine), stringify(conflict.Theirs))
 	}
+	return text
+}
 
-	if err := tmpFile.Close(); err != nil {
-		return nil, fmt.Errorf("error closing temp file: %v", err)
-	}
+// stringifyWithId returns JSON of the document with given _id
+func stringifyWithId(_id interface{}, document primitive.M) (string, error) {
+	return mongo.ParseBsonDocument(withId(_id, document))
+}
 
-	return tmpFile, nil
+// withId returns copy of the document with given _id
+func withId(_id interface{}, document primitive.M) primitive.M {
+	doc := primitive.M{"_id": _id}
+	for key, value := range document {
+		doc[key] = value
+	}
+	return doc
 }
 
 // removeField removes the specified field from a JSON string.
@@ -0,0 +1,180 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+// editorNotePrefix starts lines with notes shown above the edited
+// document, they are removed when the document is read back
+const editorNotePrefix = "// "
+
+// openEditor opens the editor with the document and returns the edited document
+func openEditor(app *core.App, rawDocument string) (string, error) {
+	return openEditorWithNotes(app, rawDocument, nil)
+}
+
+// openEditorWithNotes opens the editor with notes, e.g. validation
+// errors, written above the document and returns the edited document
+func openEditorWithNotes(app *core.App, rawDocument string, notes []string) (string, error) {
+	prettyJsonBuffer, err := mongo.IndentJson(rawDocument)
+	if err != nil {
+		return "", fmt.Errorf("error indenting JSON: %v", err)
+	}
+
+	var content bytes.Buffer
+	for _, note := range notes {
+		content.WriteString(editorNotePrefix + note + "\n")
+	}
+	content.Write(prettyJsonBuffer.Bytes())
+
+	ed, err := app.GetConfig().GetEditorCmd()
+	if err != nil {
+		return "", fmt.Errorf("error getting editor command: %v", err)
+	}
+	editor, err := exec.LookPath(ed)
+	if err != nil {
+		return "", fmt.Errorf("error looking for editor: %v", err)
+	}
+
+	tmpFile, err := writeToTempFile(content)
+	if err != nil {
+		return "", fmt.Errorf("error writing to temp file: %v", err)
+	}
+
+	updatedDocument := ""
+	app.Suspend(func() {
+		updatedDocument, err = editUntilValid(editor, tmpFile.Name())
+	})
+	var kept *keptEditError
+	if !errors.As(err, &kept) {
+		os.Remove(tmpFile.Name())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return updatedDocument, nil
+}
+
+// keptEditError is returned when the file with invalid JSON could be
+// neither reopened nor discarded, the file is kept so edits are not lost
+type keptEditError struct {
+	path string
+	err  error
+}
+
+func (e *keptEditError) Error() string {
+	return fmt.Sprintf("edited JSON is not valid: %v, changes are kept in %s", e.err, e.path)
+}
+
+// editUntilValid runs the editor on the file until it contains valid JSON,
+// on invalid JSON the parse error is printed and the editor is reopened
+// on the same file unless the user cancels, then empty string is returned.
+// It must be run when the application is suspended
+func editUntilValid(editor, path string) (string, error) {
+	for {
+		cmd := exec.Command(editor, path)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("error running editor: %v", err)
+		}
+
+		editedBytes, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading edited file: %v", err)
+		}
+		editedBytes = stripNotes(editedBytes)
+		jsonErr := util.ValidateJson(editedBytes)
+		if jsonErr == nil {
+			return string(bytes.TrimLeft(editedBytes, "\n")), nil
+		}
+
+		log.Error().Err(jsonErr).Msg("Edited JSON is not valid")
+		fmt.Printf("\nEdited JSON is not valid: %v\nReopen the editor to fix it? Changes are discarded otherwise [Y/n]: ", jsonErr)
+		answer, err := readLine(os.Stdin)
+		if err != nil {
+			log.Error().Err(err).Msg("Error reading answer")
+			return "", &keptEditError{path: path, err: jsonErr}
+		}
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "n" || answer == "no" {
+			log.Debug().Msg("Invalid edit discarded")
+			return "", nil
+		}
+	}
+}
+
+// readLine reads a line byte by byte, so nothing past the newline is
+// consumed and left unavailable to the next editor run
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// stripNotes replaces note lines at the beginning of the edited file
+// with empty lines, so positions of JSON errors match the file
+func stripNotes(edited []byte) []byte {
+	lines := bytes.SplitAfter(edited, []byte("\n"))
+	for i := 0; i < len(lines) && bytes.HasPrefix(bytes.TrimSpace(lines[i]), []byte(strings.TrimSpace(editorNotePrefix))); i++ {
+		lines[i] = []byte("\n")
+	}
+	return bytes.Join(lines, nil)
+}
+
+// compactJson removes insignificant whitespace from JSON so documents
+// can be compared, invalid JSON is returned unchanged
+func compactJson(document string) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(document)); err != nil {
+		return document
+	}
+	return compacted.String()
+}
+
+// writeToTempFile writes the JSON to a temp file and returns the file
+func writeToTempFile(bufferJson bytes.Buffer) (*os.File, error) {
+	tmpFile, err := os.CreateTemp("", "doc-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %v", err)
+	}
+
+	_, err = tmpFile.Write(bufferJson.Bytes())
+	if err != nil {
+		err = os.Remove(tmpFile.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error removing temp file: %v", err)
+		}
+		return nil, fmt.Errorf("error writing to temp file: %v", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return nil, fmt.Errorf("error closing temp file: %v", err)
+	}
+
+	return tmpFile, nil
+}
@@ -1,13 +1,23 @@
 package component
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/kopecmaciej/vi-mongo/internal/manager"
 	"github.com/kopecmaciej/vi-mongo/internal/mongo"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
@@ -24,20 +34,50 @@ type Peeker struct {
 	*core.ViewModal
 
 	docModifier *DocModifier
-	currentDoc  string
+	// state of the collection displayed in content
+	state   *mongo.CollectionState
+	current peekedDocument
+	// backStack holds documents from which references were followed
+	backStack []peekedDocument
+
+	// tree shows the document as collapsible tree instead of JSON text
+	tree      *core.TreeView
+	treeFrame *tview.Flex
+	treeMode  bool
+
+	searchModal    *modal.Search
+	search         *util.TextSearch
+	searchBackward bool
 
 	doneFunc func()
 }
 
+type peekedDocument struct {
+	db, coll string
+	_id      interface{}
+	doc      string
+	document primitive.M
+}
+
+// treeNode is a reference of the node in tree mode
+type treeNode struct {
+	key   string
+	path  string
+	value interface{}
+}
+
 // NewPeeker creates a new Peeker view
 func NewPeeker() *Peeker {
 	p := &Peeker{
 		BaseElement: core.NewBaseElement(),
 		ViewModal:   core.NewViewModal(),
 		docModifier: NewDocModifier(),
+		tree:        core.NewTreeView(),
+		searchModal: modal.NewSearchModal(),
 	}
 
 	p.SetIdentifier(PeekerComponent)
+	p.tree.SetIdentifier(PeekerComponent)
 	p.SetAfterInitFunc(p.init)
 
 	return p
@@ -51,12 +91,20 @@ func (p *Peeker) init() error {
 	if err := p.docModifier.Init(p.App); err != nil {
 		return err
 	}
+	if err := p.searchModal.Init(p.App); err != nil {
+		return err
+	}
 
 	p.handleEvents()
 
 	return nil
 }
 
+func (p *Peeker) UpdateDao(dao *mongo.Dao) {
+	p.BaseElement.UpdateDao(dao)
+	p.docModifier.UpdateDao(dao)
+}
+
 func (p *Peeker) handleEvents() {
 	go p.HandleEvents(PeekerComponent, func(event manager.EventMsg) {
 		switch event.Message.Type {
@@ -72,17 +120,33 @@ func (p *Peeker) setStaticLayout() {
 	p.SetTitleAlign(tview.AlignLeft)
 
 	p.ViewModal.AddButtons([]string{"Edit", "Close"})
+
+	p.tree.SetBorder(true)
+	p.tree.SetTitleAlign(tview.AlignLeft)
+	p.tree.SetGraphics(true)
+
+	// center the tree the same way as the view modal
+	p.treeFrame = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 1, 0, false).
+			AddItem(p.tree, 0, 1, true).
+			AddItem(nil, 1, 0, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
 }
 
 func (p *Peeker) setStyle() {
 	style := &p.App.GetStyles().DocPeeker
 	p.ViewModal.SetStyle(p.App.GetStyles())
+	p.tree.SetStyle(p.App.GetStyles())
+	p.tree.SetGraphicsColor(style.BracketColor.Color())
 	p.SetHighlightColor(style.HighlightColor.Color())
 	p.SetDocumentColors(
 		style.KeyColor.Color(),
 		style.ValueColor.Color(),
 		style.BracketColor.Color(),
 	)
+	p.applySearch()
 }
 
 func (p *Peeker) setKeybindings() {
@@ -108,6 +172,69 @@ func (p *Peeker) setKeybindings() {
 		case k.Contains(k.Peeker.Refresh, event.Name()):
 			p.setText()
 			return nil
+		case k.Contains(k.Peeker.FollowReference, event.Name()):
+			p.followReference()
+			return nil
+		case k.Contains(k.Peeker.Back, event.Name()):
+			p.back()
+			return nil
+		case k.Contains(k.Peeker.ToggleTree, event.Name()):
+			p.toggleTree()
+			return nil
+		case k.Contains(k.Peeker.Search, event.Name()):
+			p.showSearch(false)
+			return nil
+		case k.Contains(k.Peeker.SearchBackward, event.Name()):
+			p.showSearch(true)
+			return nil
+		case k.Contains(k.Peeker.NextMatch, event.Name()):
+			p.jumpToMatch(false)
+			return nil
+		case k.Contains(k.Peeker.PreviousMatch, event.Name()):
+			p.jumpToMatch(true)
+			return nil
+		}
+		return event
+	})
+
+	p.tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Peeker.ExpandNode, event.Name()):
+			p.expandNode()
+			return nil
+		case k.Contains(k.Peeker.CollapseNode, event.Name()):
+			p.collapseNode()
+			return nil
+		case k.Contains(k.Peeker.CopyHighlight, event.Name()), k.Contains(k.Peeker.CopyValue, event.Name()):
+			p.copyNode(false)
+			return nil
+		case k.Contains(k.Peeker.CopyPath, event.Name()):
+			p.copyNode(true)
+			return nil
+		case k.Contains(k.Peeker.FollowReference, event.Name()):
+			p.followNodeReference()
+			return nil
+		case k.Contains(k.Peeker.Back, event.Name()):
+			p.back()
+			return nil
+		case k.Contains(k.Peeker.ToggleTree, event.Name()):
+			p.toggleTree()
+			return nil
+		case k.Contains(k.Peeker.Search, event.Name()):
+			p.showSearch(false)
+			return nil
+		case k.Contains(k.Peeker.SearchBackward, event.Name()):
+			p.showSearch(true)
+			return nil
+		case k.Contains(k.Peeker.NextMatch, event.Name()):
+			p.jumpToMatch(false)
+			return nil
+		case k.Contains(k.Peeker.PreviousMatch, event.Name()):
+			p.jumpToMatch(true)
+			return nil
+		case event.Key() == tcell.KeyEscape:
+			p.App.Pages.RemovePage(p.GetIdentifier())
+			return nil
 		}
 		return event
 	})
@@ -126,42 +253,472 @@ func (p *Peeker) SetDoneFunc(doneFunc func()) {
 }
 
 func (p *Peeker) Render(ctx context.Context, state *mongo.CollectionState, _id interface{}) error {
-	p.MoveToTop()
 	doc, err := state.GetJsonDocById(_id)
 	if err != nil {
 		return err
 	}
 
-	p.currentDoc = doc
-	p.setText()
+	p.state = state
+	p.backStack = nil
+	p.search = nil
+	p.applySearch()
+	p.show(ctx, peekedDocument{db: state.Db, coll: state.Coll, _id: _id, doc: doc, document: state.GetDocById(_id)})
+
+	return nil
+}
+
+// RenderReference shows referenced document, if the peeker is already open
+// current document is put on the back stack
+func (p *Peeker) RenderReference(ctx context.Context, reference *mongo.Reference) error {
+	jsoned, err := mongo.ParseBsonDocument(reference.Document)
+	if err != nil {
+		return err
+	}
+	indented, err := mongo.IndentJson(jsoned)
+	if err != nil {
+		return err
+	}
+
+	if p.App.Pages.HasPage(p.GetIdentifier()) {
+		p.backStack = append(p.backStack, p.current)
+	} else {
+		p.backStack = nil
+	}
+	p.show(ctx, peekedDocument{
+		db:       reference.Db,
+		coll:     reference.Coll,
+		_id:      reference.Document["_id"],
+		doc:      indented.String(),
+		document: reference.Document,
+	})
 
-	p.App.Pages.AddPage(p.GetIdentifier(), p.ViewModal, true, true)
+	return nil
+}
+
+func (p *Peeker) show(ctx context.Context, doc peekedDocument) {
+	p.current = doc
+	p.MoveToTop()
+	p.refresh()
+
+	if !p.App.Pages.HasPage(p.GetIdentifier()) {
+		p.App.Pages.AddPage(p.GetIdentifier(), p.page(), true, true)
+	}
 	p.ViewModal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 		if buttonLabel == "Edit" {
-			updatedDoc, err := p.docModifier.Edit(ctx, state.Db, state.Coll, _id, p.currentDoc)
-			if err != nil {
-				modal.ShowError(p.App.Pages, "Error editing document", err)
-				return
-			}
-
-			if updatedDoc != "" {
-				state.UpdateRawDoc(updatedDoc)
-				p.currentDoc = updatedDoc
-				if p.doneFunc != nil {
-					p.doneFunc()
-				}
-				p.setText()
-			}
+			p.edit(ctx)
 		} else if buttonLabel == "Close" || buttonLabel == "" {
 			p.App.Pages.RemovePage(p.GetIdentifier())
 		}
 	})
-	return nil
+}
+
+func (p *Peeker) edit(ctx context.Context) {
+	current := p.current
+	err := p.docModifier.Edit(ctx, current.db, current.coll, current._id, current.doc, func(updatedDoc string) {
+		p.current.doc = updatedDoc
+		if document, err := mongo.ParseJsonToBson(updatedDoc); err == nil {
+			p.current.document = document
+		}
+		if p.state != nil && p.state.Db == current.db && p.state.Coll == current.coll {
+			p.state.UpdateRawDoc(updatedDoc)
+			if p.doneFunc != nil {
+				p.doneFunc()
+			}
+		}
+		p.refresh()
+	})
+	if err != nil {
+		modal.ShowError(p.App.Pages, "Error editing document", err)
+	}
+}
+
+// followReference opens the document referenced by ObjectID from the selected line
+func (p *Peeker) followReference() {
+	line, ok := p.GetSelectedText("full")
+	if !ok {
+		return
+	}
+	id, ok := mongo.ExtractObjectID(line)
+	if !ok {
+		modal.ShowInfo(p.App.Pages, "Selected value is not an ObjectID")
+		return
+	}
+	doc, err := mongo.ParseJsonToBson(p.current.doc)
+	if err != nil {
+		modal.ShowError(p.App.Pages, "Error parsing document", err)
+		return
+	}
+	key, _, _ := strings.Cut(line, ":")
+	p.followReferenceAt(mongo.FindFieldPath(doc, strings.Trim(key, `" `), id), id)
+}
+
+// followReferenceAt opens the document referenced by the field
+func (p *Peeker) followReferenceAt(field string, id primitive.ObjectID) {
+	ctx := context.Background()
+	reference, err := p.Dao.FindReference(ctx, p.current.db, p.current.coll, field, id)
+	if err != nil {
+		modal.ShowError(p.App.Pages, "Error following reference", err)
+		return
+	}
+	if err := p.RenderReference(ctx, reference); err != nil {
+		modal.ShowError(p.App.Pages, "Error showing referenced document", err)
+	}
+}
+
+// back shows the document from which the reference was followed
+func (p *Peeker) back() {
+	if len(p.backStack) == 0 {
+		return
+	}
+	p.current = p.backStack[len(p.backStack)-1]
+	p.backStack = p.backStack[:len(p.backStack)-1]
+	p.MoveToTop()
+	p.refresh()
+}
+
+// refresh renders current document in the text and tree view
+func (p *Peeker) refresh() {
+	p.setText()
+	if p.treeMode {
+		p.renderTree()
+	}
+	p.setTitle()
+}
+
+func (p *Peeker) setTitle() {
+	title := "Document Details"
+	if len(p.backStack) > 0 || p.state == nil || p.state.Db != p.current.db || p.state.Coll != p.current.coll {
+		title = fmt.Sprintf("Document Details - %s.%s", p.current.db, p.current.coll)
+	}
+	if p.search == nil {
+		p.tree.SetTitle(" " + title + " ")
+		p.SetTitle(title)
+		return
+	}
+	nodes := p.treeNodes()
+	p.tree.SetTitle(" " + title + " - " + searchStatus(p.treeMatches(nodes), slices.Index(nodes, p.tree.GetCurrentNode())) + " ")
+	p.SetTitle(title + " - " + searchStatus(p.search.FindMatches(p.GetLines()), p.GetSelectedIndex()))
+}
+
+// showSearch opens the search modal and moves to the first match
+func (p *Peeker) showSearch(backward bool) {
+	p.searchModal.Render(p.search, backward, func(search *util.TextSearch, backward bool) {
+		p.search = search
+		p.searchBackward = backward
+		p.applySearch()
+		p.jumpToMatch(false)
+	})
+}
+
+func (p *Peeker) applySearch() {
+	p.refreshTreeTexts()
+	if p.search == nil {
+		p.SetSearch(nil, tcell.ColorDefault)
+		return
+	}
+	p.SetSearch(p.search, p.App.GetStyles().DocPeeker.SearchMatchColor.Color())
+}
+
+// jumpToMatch selects the next line or tree node that matches the search,
+// reverse moves in the opposite direction than the search was started
+func (p *Peeker) jumpToMatch(reverse bool) {
+	if p.treeMode {
+		p.jumpToTreeMatch(reverse)
+		return
+	}
+	if p.search == nil {
+		p.setTitle()
+		return
+	}
+	matches := p.search.FindMatches(p.GetLines())
+	next := util.NextMatch(matches, p.GetSelectedIndex(), p.searchBackward != reverse)
+	if next == -1 {
+		p.setTitle()
+		modal.ShowInfo(p.App.Pages, "Pattern not found: "+p.search.Pattern)
+		return
+	}
+	p.SelectLine(matches[next])
+	p.setTitle()
+}
+
+// jumpToTreeMatch selects the next matching node of the tree,
+// collapsed parents of the node are expanded
+func (p *Peeker) jumpToTreeMatch(reverse bool) {
+	if p.search == nil {
+		p.setTitle()
+		return
+	}
+	nodes := p.treeNodes()
+	matches := p.treeMatches(nodes)
+	next := util.NextMatch(matches, slices.Index(nodes, p.tree.GetCurrentNode()), p.searchBackward != reverse)
+	if next == -1 {
+		p.setTitle()
+		modal.ShowInfo(p.App.Pages, "Pattern not found: "+p.search.Pattern)
+		return
+	}
+	node := nodes[matches[next]]
+	path := p.tree.GetPath(node)
+	for _, parent := range path[:len(path)-1] {
+		parent.SetExpanded(true)
+	}
+	p.tree.SetCurrentNode(node)
+	p.setTitle()
+}
+
+// treeNodes returns all nodes of the tree except the root, in the order
+// they are shown when the whole tree is expanded
+func (p *Peeker) treeNodes() []*tview.TreeNode {
+	nodes := []*tview.TreeNode{}
+	root := p.tree.GetRoot()
+	if root == nil {
+		return nodes
+	}
+	root.Walk(func(node, parent *tview.TreeNode) bool {
+		if parent != nil {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// treeMatches returns indexes of nodes whose key or value matches the search
+func (p *Peeker) treeMatches(nodes []*tview.TreeNode) []int {
+	texts := make([]string, len(nodes))
+	for i, node := range nodes {
+		if ref, ok := node.GetReference().(treeNode); ok {
+			texts[i] = treeNodeText(ref)
+		}
+	}
+	return p.search.FindMatches(texts)
+}
+
+// treeNodeText returns plain text of the node that is searched
+func treeNodeText(node treeNode) string {
+	switch node.value.(type) {
+	case primitive.M, map[string]interface{}, primitive.D, primitive.A, []interface{}:
+		return node.key
+	}
+	return node.key + ": " + util.GetValueByType(node.value)
+}
+
+// refreshTreeTexts formats texts of all tree nodes again, so they are
+// highlighted with the current search
+func (p *Peeker) refreshTreeTexts() {
+	for _, node := range p.treeNodes() {
+		if ref, ok := node.GetReference().(treeNode); ok {
+			node.SetText(p.formatTreeNode(ref.key, ref.value))
+		}
+	}
+}
+
+// page returns the primitive that is displayed in current mode
+func (p *Peeker) page() tview.Primitive {
+	if p.treeMode {
+		return p.treeFrame
+	}
+	return p.ViewModal
+}
+
+// toggleTree switches between JSON text and tree of the document
+func (p *Peeker) toggleTree() {
+	p.treeMode = !p.treeMode
+	p.refresh()
+	// page is replaced directly to keep the focus that should be restored after closing
+	p.App.Pages.Pages.AddPage(string(p.GetIdentifier()), p.page(), true, true)
+	p.App.FocusChanged(p.page())
+}
+
+// renderTree builds the tree of current document, root and top level fields are expanded
+func (p *Peeker) renderTree() {
+	root := tview.NewTreeNode(fmt.Sprintf("%s.%s", p.current.db, p.current.coll)).
+		SetReference(treeNode{path: "", value: p.current.document}).
+		SetColor(p.App.GetStyles().DocPeeker.BracketColor.Color())
+	p.addTreeChildren(root, "", p.current.document)
+
+	p.tree.SetRoot(root)
+	p.tree.SetCurrentNode(root)
+}
+
+func (p *Peeker) addTreeChildren(parent *tview.TreeNode, path string, value interface{}) {
+	keys, values := treeEntries(value)
+	for i, key := range keys {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		child := tview.NewTreeNode(p.formatTreeNode(key, values[i])).
+			SetReference(treeNode{key: key, path: childPath, value: values[i]}).
+			SetSelectable(true)
+		if childKeys, _ := treeEntries(values[i]); len(childKeys) > 0 {
+			p.addTreeChildren(child, childPath, values[i])
+			child.SetExpanded(false)
+		}
+		parent.AddChild(child)
+	}
+}
+
+// formatTreeNode returns colored text of the node with type or length of the value
+func (p *Peeker) formatTreeNode(key string, value interface{}) string {
+	style := p.App.GetStyles().DocPeeker
+	keyColor, valueColor, typeColor := style.KeyColor.Color().String(), style.ValueColor.Color().String(), style.BracketColor.Color().String()
+
+	keys, _ := treeEntries(value)
+	switch value.(type) {
+	case primitive.M, map[string]interface{}, primitive.D:
+		return fmt.Sprintf("[%s]%s[-] [%s]Object(%d)[-]", keyColor, p.highlightTreeText(key), typeColor, len(keys))
+	case primitive.A, []interface{}:
+		return fmt.Sprintf("[%s]%s[-] [%s]Array(%d)[-]", keyColor, p.highlightTreeText(key), typeColor, len(keys))
+	default:
+		return fmt.Sprintf("[%s]%s[-]: [%s]%s[-] [%s]%s[-]", keyColor, p.highlightTreeText(key),
+			valueColor, p.highlightTreeText(util.GetValueByType(value)), typeColor, util.GetMongoType(value))
+	}
+}
+
+// highlightTreeText escapes the text and highlights matches of the search,
+// matches are marked before escaping, so they can't break escaped brackets
+func (p *Peeker) highlightTreeText(text string) string {
+	if p.search == nil {
+		return tview.Escape(text)
+	}
+	marked := p.search.Highlight(text, "\x00", "\x01")
+	startTag := fmt.Sprintf("[:%s]", p.App.GetStyles().DocPeeker.SearchMatchColor.Color().CSS())
+	return strings.NewReplacer("\x00", startTag, "\x01", "[:-]").Replace(tview.Escape(marked))
+}
+
+// treeEntries returns keys and values of objects and arrays, _id is always first
+func treeEntries(value interface{}) ([]string, []interface{}) {
+	var obj map[string]interface{}
+	switch v := value.(type) {
+	case primitive.M:
+		obj = v
+	case map[string]interface{}:
+		obj = v
+	case primitive.D:
+		obj = v.Map()
+	case primitive.A:
+		return arrayEntries(v)
+	case []interface{}:
+		return arrayEntries(v)
+	default:
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == "_id" || keys[j] == "_id" {
+			return keys[i] == "_id"
+		}
+		return keys[i] < keys[j]
+	})
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = obj[key]
+	}
+	return keys, values
+}
+
+func arrayEntries(arr []interface{}) ([]string, []interface{}) {
+	keys := make([]string, len(arr))
+	for i := range arr {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys, arr
+}
+
+func (p *Peeker) expandNode() {
+	node := p.tree.GetCurrentNode()
+	if node == nil {
+		return
+	}
+	if len(node.GetChildren()) > 0 && !node.IsExpanded() {
+		node.SetExpanded(true)
+	}
+}
+
+// collapseNode collapses expanded node or moves to its parent
+func (p *Peeker) collapseNode() {
+	node := p.tree.GetCurrentNode()
+	if node == nil {
+		return
+	}
+	if len(node.GetChildren()) > 0 && node.IsExpanded() {
+		node.SetExpanded(false)
+		return
+	}
+	path := p.tree.GetPath(node)
+	if len(path) > 1 {
+		parent := path[len(path)-2]
+		parent.SetExpanded(false)
+		p.tree.SetCurrentNode(parent)
+	}
+}
+
+// copyNode copies the dotted path of the node or JSON of its value
+func (p *Peeker) copyNode(path bool) {
+	node, ok := p.currentTreeNode()
+	if !ok {
+		return
+	}
+	text := node.path
+	if !path {
+		var err error
+		text, err = stringifyValue(node.value)
+		if err != nil {
+			modal.ShowError(p.App.Pages, "Error copying value", err)
+			return
+		}
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		modal.ShowError(p.App.Pages, "Error copying value", err)
+	}
+}
+
+func (p *Peeker) followNodeReference() {
+	node, ok := p.currentTreeNode()
+	if !ok {
+		return
+	}
+	id, ok := node.value.(primitive.ObjectID)
+	if !ok {
+		modal.ShowInfo(p.App.Pages, "Selected value is not an ObjectID")
+		return
+	}
+	p.followReferenceAt(node.path, id)
+}
+
+func (p *Peeker) currentTreeNode() (treeNode, bool) {
+	current := p.tree.GetCurrentNode()
+	if current == nil {
+		return treeNode{}, false
+	}
+	node, ok := current.GetReference().(treeNode)
+	return node, ok
+}
+
+// stringifyValue returns relaxed extended JSON of any BSON value
+func stringifyValue(value interface{}) (string, error) {
+	extJson, err := bson.MarshalExtJSON(primitive.M{"v": value}, false, false)
+	if err != nil {
+		return "", err
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(extJson, &wrapper); err != nil {
+		return "", err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, wrapper["v"], "", "  "); err != nil {
+		return "", err
+	}
+	return indented.String(), nil
 }
 
 func (p *Peeker) setText() {
 	p.ViewModal.SetText(primitives.Text{
-		Content: p.currentDoc,
+		Content: p.current.doc,
 		Color:   p.App.GetStyles().DocPeeker.ValueColor.Color(),
 		Align:   tview.AlignLeft,
 	})
@@ -15,6 +15,7 @@ type (
 
 		Pages         *Pages
 		dao           *mongo.Dao
+		journal       *mongo.Journal
 		manager       *manager.ElementManager
 		styles        *config.Styles
 		config        *config.Config
@@ -40,6 +41,7 @@ func NewApp(appConfig *config.Config) *App {
 		styles:      styles,
 		config:      appConfig,
 		keys:        keyBindings,
+		journal:     mongo.NewJournal(),
 	}
 
 	app.Pages = NewPages(app.manager, app)
@@ -103,8 +105,15 @@ func (a *App) GetDao() *mongo.Dao {
 	return a.dao
 }
 
+// SetDao sets the dao and starts a new journal, as operations
+// from previous connection can't be undone with the new one
 func (a *App) SetDao(dao *mongo.Dao) {
 	a.dao = dao
+	a.journal = mongo.NewJournal()
+}
+
+func (a *App) GetJournal() *mongo.Journal {
+	return a.journal
 }
 
 func (a *App) GetManager() *manager.ElementManager {
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MinRefreshInterval is the shortest interval between refreshes
+const MinRefreshInterval = time.Second
+
+// Refresher periodically fetches data of a page and updates the page
+// on the main goroutine, until it's stopped
+type Refresher struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Start stops previous refreshing and starts calling fetch every interval,
+// the first call is made immediately. Fetch gets context that times out
+// after the interval and returns the function that updates the page.
+// Intervals shorter than MinRefreshInterval are clamped to it
+func (r *Refresher) Start(app *App, interval time.Duration, fetch func(ctx context.Context) func()) {
+	r.Stop()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interval = max(interval, MinRefreshInterval)
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	go r.loop(ctx, app, interval, fetch)
+}
+
+// Stop stops refreshing, update of already started fetch is dropped
+func (r *Refresher) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+func (r *Refresher) loop(ctx context.Context, app *App, interval time.Duration, fetch func(ctx context.Context) func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, interval)
+		update := fetch(fetchCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		app.QueueUpdateDraw(func() {
+			// page could be closed while the update was queued
+			if ctx.Err() == nil {
+				update()
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 type Table struct {
 	*tview.Table
+
+	// highlightFunc returns text of the cell as it should be drawn
+	highlightFunc func(cell *tview.TableCell) string
 }
 
 func NewTable() *Table {
@@ -23,6 +26,36 @@ func (t *Table) SetStyle(style *config.Styles) {
 	t.SetFocusStyle(tcell.StyleDefault.Foreground(style.Global.FocusColor.Color()).Background(style.Global.BackgroundColor.Color()))
 }
 
+// SetHighlightFunc sets the function that returns text of the cell as it
+// should be drawn, like with highlighted parts. Text of cells is changed
+// only for drawing, so it can be still read as it is. Nil disables it
+func (t *Table) SetHighlightFunc(highlightFunc func(cell *tview.TableCell) string) {
+	t.highlightFunc = highlightFunc
+}
+
+// Draw draws the table, text of cells is formatted by highlight function
+func (t *Table) Draw(screen tcell.Screen) {
+	if t.highlightFunc == nil {
+		t.Table.Draw(screen)
+		return
+	}
+
+	originals := map[*tview.TableCell]string{}
+	for row := 0; row < t.GetRowCount(); row++ {
+		for col := 0; col < t.GetColumnCount(); col++ {
+			cell := t.GetCell(row, col)
+			if highlighted := t.highlightFunc(cell); highlighted != cell.Text {
+				originals[cell] = cell.Text
+				cell.Text = highlighted
+			}
+		}
+	}
+	t.Table.Draw(screen)
+	for cell, text := range originals {
+		cell.Text = text
+	}
+}
+
 // MoveUpUntil moves the selection up until a condition is met
 func (t *Table) MoveUpUntil(row, col int, condition func(cell *tview.TableCell) bool) {
 	for row > 0 {
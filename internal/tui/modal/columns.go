@@ -0,0 +1,175 @@
+package modal
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/config"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+)
+
+const (
+	ColumnsModal = "Columns"
+)
+
+// Columns is a modal for hiding, reordering and pinning columns of the table
+type Columns struct {
+	*core.BaseElement
+	*primitives.ListModal
+
+	style *config.HistoryStyle
+
+	layout  *config.ColumnLayout
+	columns []string
+	// onChange is called after every change of the layout
+	onChange func()
+}
+
+func NewColumnsModal() *Columns {
+	c := &Columns{
+		BaseElement: core.NewBaseElement(),
+		ListModal:   primitives.NewListModal(),
+	}
+
+	c.SetIdentifier(ColumnsModal)
+	c.SetAfterInitFunc(c.init)
+
+	return c
+}
+
+func (c *Columns) init() error {
+	c.setStaticLayout()
+	c.setStyle()
+	c.setKeybindings()
+
+	c.handleEvents()
+
+	return nil
+}
+
+func (c *Columns) setStaticLayout() {
+	c.SetTitle(" Columns ")
+	c.SetBorder(true)
+	c.ShowSecondaryText(false)
+}
+
+func (c *Columns) setStyle() {
+	c.style = &c.App.GetStyles().History
+	globalBackground := c.App.GetStyles().Global.BackgroundColor.Color()
+
+	mainStyle := tcell.StyleDefault.
+		Foreground(c.style.TextColor.Color()).
+		Background(globalBackground)
+	c.SetMainTextStyle(mainStyle)
+
+	selectedStyle := tcell.StyleDefault.
+		Foreground(c.style.SelectedTextColor.Color()).
+		Background(c.style.SelectedBackgroundColor.Color())
+	c.SetSelectedStyle(selectedStyle)
+}
+
+func (c *Columns) setKeybindings() {
+	keys := c.App.GetKeys()
+	c.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case keys.Contains(keys.Columns.ToggleHidden, event.Name()):
+			c.toggleHidden()
+			return nil
+		case keys.Contains(keys.Columns.TogglePinned, event.Name()):
+			c.togglePinned()
+			return nil
+		case keys.Contains(keys.Columns.MoveUp, event.Name()):
+			c.move(-1)
+			return nil
+		case keys.Contains(keys.Columns.MoveDown, event.Name()):
+			c.move(1)
+			return nil
+		case keys.Contains(keys.Columns.CloseColumns, event.Name()):
+			c.App.Pages.RemovePage(c.GetIdentifier())
+			return nil
+		}
+		return event
+	})
+}
+
+func (c *Columns) handleEvents() {
+	go c.HandleEvents(c.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			c.setStyle()
+		}
+	})
+}
+
+// Render shows columns of the layout, onChange is called
+// every time the layout is changed
+func (c *Columns) Render(layout *config.ColumnLayout, columns []string, onChange func()) {
+	c.layout = layout
+	c.columns = columns
+	c.onChange = onChange
+
+	c.renderList("")
+
+	c.App.Pages.AddPage(c.GetIdentifier(), c, true, true)
+}
+
+func (c *Columns) renderList(selected string) {
+	c.Clear()
+	for i, column := range c.layout.Arrange(c.columns) {
+		text := "✓ " + column
+		if c.layout.IsHidden(column) {
+			text = "  " + column
+		}
+		if c.layout.IsPinned(column) {
+			text += " (pinned)"
+		}
+		c.AddItem(text, "", 0, nil)
+		if column == selected {
+			c.SetCurrentItem(i)
+		}
+	}
+}
+
+func (c *Columns) currentColumn() string {
+	arranged := c.layout.Arrange(c.columns)
+	index := c.GetCurrentItem()
+	if index < 0 || index >= len(arranged) {
+		return ""
+	}
+	return arranged[index]
+}
+
+func (c *Columns) toggleHidden() {
+	column := c.currentColumn()
+	// at least one column has to stay visible
+	if column == "" || !c.layout.IsHidden(column) && len(c.layout.Visible(c.columns)) == 1 {
+		return
+	}
+	c.layout.ToggleHidden(column)
+	c.update(column)
+}
+
+func (c *Columns) togglePinned() {
+	column := c.currentColumn()
+	if column == "" {
+		return
+	}
+	c.layout.TogglePinned(column)
+	c.update(column)
+}
+
+func (c *Columns) move(delta int) {
+	column := c.currentColumn()
+	if column == "" {
+		return
+	}
+	c.layout.Move(c.columns, column, delta)
+	c.update(column)
+}
+
+func (c *Columns) update(selected string) {
+	c.renderList(selected)
+	if c.onChange != nil {
+		c.onChange()
+	}
+}
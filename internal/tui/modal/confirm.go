@@ -0,0 +1,99 @@
+package modal
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+)
+
+// Confirm is a scrollable modal that presents a longer text (e.g. list of changes)
+// and asks the user to accept or reject it
+type Confirm struct {
+	*core.BaseElement
+	*core.ViewModal
+}
+
+func NewConfirmModal(id tview.Identifier) *Confirm {
+	cm := &Confirm{
+		BaseElement: core.NewBaseElement(),
+		ViewModal:   core.NewViewModal(),
+	}
+
+	cm.SetIdentifier(id)
+	cm.SetAfterInitFunc(cm.init)
+
+	return cm
+}
+
+func (c *Confirm) init() error {
+	c.setStaticLayout()
+	c.setStyle()
+	c.setKeybindings()
+
+	c.handleEvents()
+
+	return nil
+}
+
+func (c *Confirm) setStaticLayout() {
+	c.SetBorder(true)
+	c.SetTitle(" Confirm ")
+	c.SetTitleAlign(tview.AlignLeft)
+}
+
+func (c *Confirm) setStyle() {
+	styles := c.App.GetStyles()
+	c.ViewModal.SetStyle(styles)
+	c.SetHighlightColor(styles.DocPeeker.HighlightColor.Color())
+	c.SetDocumentColors(
+		styles.DocPeeker.KeyColor.Color(),
+		styles.DocPeeker.ValueColor.Color(),
+		styles.DocPeeker.BracketColor.Color(),
+	)
+}
+
+func (c *Confirm) setKeybindings() {
+	k := c.App.GetKeys()
+	c.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Peeker.MoveToTop, event.Name()):
+			c.MoveToTop()
+			return nil
+		case k.Contains(k.Peeker.MoveToBottom, event.Name()):
+			c.MoveToBottom()
+			return nil
+		}
+		return event
+	})
+}
+
+func (c *Confirm) handleEvents() {
+	go c.HandleEvents(c.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			c.setStyle()
+		}
+	})
+}
+
+// Render shows the modal with given title, content and buttons,
+// doneFunc is called with the label of pressed button and the modal is closed.
+func (c *Confirm) Render(title, content string, buttons []string, doneFunc func(buttonLabel string)) {
+	c.SetTitle(" " + title + " ")
+	c.MoveToTop()
+	c.SetText(primitives.Text{
+		Content: content,
+		Color:   c.App.GetStyles().DocPeeker.ValueColor.Color(),
+		Align:   tview.AlignLeft,
+	})
+	c.ClearButtons()
+	c.AddButtons(buttons)
+	c.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		c.App.Pages.RemovePage(c.GetIdentifier())
+		doneFunc(buttonLabel)
+	})
+
+	c.App.Pages.AddPage(c.GetIdentifier(), c, true, true)
+}
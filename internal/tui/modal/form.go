@@ -0,0 +1,98 @@
+package modal
+
+import (
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+)
+
+// Form is a modal with a form for actions that need more than one value
+type Form struct {
+	*core.BaseElement
+	*core.Flex
+
+	form *core.Form
+}
+
+func NewFormModal(id tview.Identifier) *Form {
+	f := &Form{
+		BaseElement: core.NewBaseElement(),
+		Flex:        core.NewFlex(),
+		form:        core.NewForm(),
+	}
+
+	f.SetIdentifier(id)
+	f.SetAfterInitFunc(f.init)
+
+	return f
+}
+
+func (f *Form) init() error {
+	f.setStaticLayout()
+	f.setStyle()
+
+	f.handleEvents()
+
+	return nil
+}
+
+func (f *Form) setStaticLayout() {
+	f.form.SetBorder(true)
+	f.form.SetTitleAlign(tview.AlignLeft)
+	f.form.SetBorderPadding(1, 1, 2, 2)
+	f.form.SetCancelFunc(f.close)
+}
+
+func (f *Form) setStyle() {
+	styles := f.App.GetStyles()
+	f.form.SetStyle(styles)
+	f.form.SetLabelColor(styles.Others.ModalTextColor.Color())
+	f.form.SetFieldBackgroundColor(styles.Global.ContrastBackgroundColor.Color())
+	f.form.SetFieldTextColor(styles.Global.TextColor.Color())
+}
+
+func (f *Form) handleEvents() {
+	go f.HandleEvents(f.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			f.setStyle()
+		}
+	})
+}
+
+// Render shows the form with fields added by addFields, onSubmit is called
+// with the form when it's submitted, the modal is closed only if it
+// returns no error, otherwise the error is shown above the form
+func (f *Form) Render(title, submitLabel string, addFields func(form *tview.Form), onSubmit func(form *tview.Form) error) {
+	f.form.Clear(true)
+	f.form.SetTitle(" " + title + " ")
+	addFields(f.form.Form)
+	f.form.AddButton(submitLabel, func() {
+		if err := onSubmit(f.form.Form); err != nil {
+			ShowError(f.App.Pages, title+" failed", err)
+			return
+		}
+		f.close()
+	})
+	f.form.AddButton("Cancel", f.close)
+	f.setStyle()
+	f.form.SetFocus(0)
+
+	// every field takes two rows, buttons, borders and padding take the rest
+	height := f.form.GetFormItemCount()*2 + 5
+
+	f.Flex.Clear()
+	f.Flex.SetDirection(tview.FlexRow)
+	f.Flex.AddItem(nil, 0, 1, false)
+	f.Flex.AddItem(tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(f.form, 70, 0, true).
+		AddItem(nil, 0, 1, false), height, 0, true)
+	f.Flex.AddItem(nil, 0, 1, false)
+
+	f.App.Pages.AddPage(f.GetIdentifier(), f, true, true)
+}
+
+func (f *Form) close() {
+	f.App.Pages.RemovePage(f.GetIdentifier())
+}
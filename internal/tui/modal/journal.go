@@ -0,0 +1,115 @@
+package modal
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/config"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+)
+
+const (
+	JournalModal = "Journal"
+)
+
+// Journal is a modal with the list of recent write operations
+// that can be undone
+type Journal struct {
+	*core.BaseElement
+	*primitives.ListModal
+
+	style *config.HistoryStyle
+
+	// onSelect is called with the index of the entry that should be undone
+	onSelect func(index int)
+}
+
+func NewJournalModal() *Journal {
+	j := &Journal{
+		BaseElement: core.NewBaseElement(),
+		ListModal:   primitives.NewListModal(),
+	}
+
+	j.SetIdentifier(JournalModal)
+	j.SetAfterInitFunc(j.init)
+
+	return j
+}
+
+func (j *Journal) init() error {
+	j.setStaticLayout()
+	j.setStyle()
+	j.setKeybindings()
+
+	j.handleEvents()
+
+	return nil
+}
+
+func (j *Journal) setStaticLayout() {
+	j.SetTitle(" Recent changes ")
+	j.SetBorder(true)
+	j.ShowSecondaryText(false)
+}
+
+func (j *Journal) setStyle() {
+	j.style = &j.App.GetStyles().History
+	globalBackground := j.App.GetStyles().Global.BackgroundColor.Color()
+
+	mainStyle := tcell.StyleDefault.
+		Foreground(j.style.TextColor.Color()).
+		Background(globalBackground)
+	j.SetMainTextStyle(mainStyle)
+
+	selectedStyle := tcell.StyleDefault.
+		Foreground(j.style.SelectedTextColor.Color()).
+		Background(j.style.SelectedBackgroundColor.Color())
+	j.SetSelectedStyle(selectedStyle)
+}
+
+func (j *Journal) setKeybindings() {
+	keys := j.App.GetKeys()
+	j.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case keys.Contains(keys.Journal.UndoEntry, event.Name()):
+			index := j.GetCurrentItem()
+			j.App.Pages.RemovePage(j.GetIdentifier())
+			if j.onSelect != nil {
+				j.onSelect(index)
+			}
+			return nil
+		case keys.Contains(keys.Journal.CloseJournal, event.Name()):
+			j.App.Pages.RemovePage(j.GetIdentifier())
+			return nil
+		}
+		return event
+	})
+}
+
+func (j *Journal) handleEvents() {
+	go j.HandleEvents(j.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			j.setStyle()
+		}
+	})
+}
+
+// Render shows the entries, starting from the most recent one,
+// onSelect is called with the index of the selected entry
+func (j *Journal) Render(entries []mongo.JournalEntry, onSelect func(index int)) {
+	j.Clear()
+	j.onSelect = onSelect
+
+	if len(entries) == 0 {
+		ShowInfo(j.App.Pages, "No changes to undo")
+		return
+	}
+
+	for _, entry := range entries {
+		j.AddItem(entry.String(), "", 0, nil)
+	}
+
+	j.App.Pages.AddPage(j.GetIdentifier(), j, true, true)
+}
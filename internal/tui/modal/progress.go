@@ -0,0 +1,95 @@
+package modal
+
+import (
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+)
+
+const (
+	ProgressModal = "ProgressModal"
+)
+
+// Progress is a modal that shows progress of a long running operation,
+// which can be cancelled until it's finished
+type Progress struct {
+	*core.BaseElement
+	*core.Modal
+
+	cancel   func()
+	finished bool
+}
+
+func NewProgressModal() *Progress {
+	p := &Progress{
+		BaseElement: core.NewBaseElement(),
+		Modal:       core.NewModal(),
+	}
+
+	p.SetIdentifier(ProgressModal)
+	p.SetAfterInitFunc(p.init)
+
+	return p
+}
+
+func (p *Progress) init() error {
+	p.setStaticLayout()
+	p.setStyle()
+
+	p.handleEvents()
+
+	return nil
+}
+
+func (p *Progress) setStaticLayout() {
+	p.SetBorder(true)
+	p.SetBorderPadding(0, 0, 1, 1)
+	p.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if p.finished {
+			p.App.Pages.RemovePage(p.GetIdentifier())
+			return
+		}
+		p.cancel()
+	})
+}
+
+func (p *Progress) setStyle() {
+	p.SetStyle(p.App.GetStyles())
+}
+
+func (p *Progress) handleEvents() {
+	go p.HandleEvents(p.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			p.setStyle()
+		}
+	})
+}
+
+// Render shows the modal, cancel is called when Cancel button is pressed
+func (p *Progress) Render(title, text string, cancel func()) {
+	p.cancel = cancel
+	p.finished = false
+	p.SetTitle(" " + title + " ")
+	p.SetText(tview.Escape(text))
+	p.ClearButtons()
+	p.AddButtons([]string{"Cancel"})
+
+	p.App.Pages.AddPage(p.GetIdentifier(), p, true, true)
+}
+
+// Update changes the text of the modal, it has to be called
+// from the main goroutine
+func (p *Progress) Update(text string) {
+	p.SetText(tview.Escape(text))
+}
+
+// Finish shows the final text of the operation, after it the modal
+// can be only closed
+func (p *Progress) Finish(text string) {
+	p.finished = true
+	p.SetText(tview.Escape(text))
+	p.ClearButtons()
+	p.AddButtons([]string{"Close"})
+	p.SetFocus(0)
+}
@@ -0,0 +1,114 @@
+package modal
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/config"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+)
+
+const (
+	QuickFilterModal = "QuickFilter"
+)
+
+// FilterOption is a single condition that can be chosen in QuickFilter modal
+type FilterOption struct {
+	Label    string
+	Operator string
+}
+
+// QuickFilter is a modal with conditions that can be built from the selected value
+type QuickFilter struct {
+	*core.BaseElement
+	*primitives.ListModal
+
+	style *config.HistoryStyle
+
+	options  []FilterOption
+	onSelect func(option FilterOption)
+}
+
+func NewQuickFilterModal() *QuickFilter {
+	q := &QuickFilter{
+		BaseElement: core.NewBaseElement(),
+		ListModal:   primitives.NewListModal(),
+	}
+
+	q.SetIdentifier(QuickFilterModal)
+	q.SetAfterInitFunc(q.init)
+
+	return q
+}
+
+func (q *QuickFilter) init() error {
+	q.setStaticLayout()
+	q.setStyle()
+	q.setKeybindings()
+
+	q.handleEvents()
+
+	return nil
+}
+
+func (q *QuickFilter) setStaticLayout() {
+	q.SetTitle(" Quick filter ")
+	q.SetBorder(true)
+	q.ShowSecondaryText(false)
+}
+
+func (q *QuickFilter) setStyle() {
+	q.style = &q.App.GetStyles().History
+	globalBackground := q.App.GetStyles().Global.BackgroundColor.Color()
+
+	mainStyle := tcell.StyleDefault.
+		Foreground(q.style.TextColor.Color()).
+		Background(globalBackground)
+	q.SetMainTextStyle(mainStyle)
+
+	selectedStyle := tcell.StyleDefault.
+		Foreground(q.style.SelectedTextColor.Color()).
+		Background(q.style.SelectedBackgroundColor.Color())
+	q.SetSelectedStyle(selectedStyle)
+}
+
+func (q *QuickFilter) setKeybindings() {
+	keys := q.App.GetKeys()
+	q.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case keys.Contains(keys.QuickFilter.ApplyFilter, event.Name()):
+			index := q.GetCurrentItem()
+			q.App.Pages.RemovePage(q.GetIdentifier())
+			if index >= 0 && index < len(q.options) && q.onSelect != nil {
+				q.onSelect(q.options[index])
+			}
+			return nil
+		case keys.Contains(keys.QuickFilter.CloseQuickFilter, event.Name()):
+			q.App.Pages.RemovePage(q.GetIdentifier())
+			return nil
+		}
+		return event
+	})
+}
+
+func (q *QuickFilter) handleEvents() {
+	go q.HandleEvents(q.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			q.setStyle()
+		}
+	})
+}
+
+// Render shows the options, onSelect is called with the chosen one
+func (q *QuickFilter) Render(options []FilterOption, onSelect func(option FilterOption)) {
+	q.Clear()
+	q.options = options
+	q.onSelect = onSelect
+
+	for _, option := range options {
+		q.AddItem(option.Label, "", 0, nil)
+	}
+
+	q.App.Pages.AddPage(q.GetIdentifier(), q, true, true)
+}
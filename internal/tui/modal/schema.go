@@ -0,0 +1,123 @@
+package modal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+)
+
+const (
+	SchemaModal = "SchemaModal"
+)
+
+// Schema is a modal with results of the schema analysis of a collection
+type Schema struct {
+	*core.BaseElement
+	*primitives.ViewModal
+}
+
+func NewSchemaModal() *Schema {
+	s := &Schema{
+		BaseElement: core.NewBaseElement(),
+		ViewModal:   primitives.NewViewModal(),
+	}
+
+	s.SetIdentifier(SchemaModal)
+	s.SetAfterInitFunc(s.init)
+
+	return s
+}
+
+func (s *Schema) init() error {
+	s.setStaticLayout()
+	s.setStyle()
+
+	s.handleEvents()
+
+	return nil
+}
+
+func (s *Schema) setStaticLayout() {
+	s.SetBorder(true)
+	s.SetTitleAlign(tview.AlignLeft)
+	s.AddButtons([]string{"Close"})
+	s.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		s.App.Pages.RemovePage(s.GetIdentifier())
+	})
+}
+
+func (s *Schema) setStyle() {
+	styles := s.App.GetStyles()
+	s.ViewModal.SetBackgroundColor(styles.Global.BackgroundColor.Color())
+	s.ViewModal.SetTextColor(styles.Global.TextColor.Color())
+	s.ViewModal.SetBorderColor(styles.Global.BorderColor.Color())
+	s.ViewModal.SetButtonBackgroundColor(styles.Global.BackgroundColor.Color())
+	s.ViewModal.SetButtonTextColor(styles.Global.TextColor.Color())
+	s.SetHighlightColor(styles.DocPeeker.HighlightColor.Color())
+}
+
+func (s *Schema) handleEvents() {
+	go s.HandleEvents(s.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			s.setStyle()
+		}
+	})
+}
+
+// Render shows the schema of the collection
+func (s *Schema) Render(db, coll string, schema *mongo.Schema) {
+	styles := s.App.GetStyles().Others
+	primary, secondary := styles.ModalTextColor.Color(), styles.ModalSecondaryTextColor.Color()
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "[%s]Sampled documents:[%s] %d\n", primary, secondary, schema.SampleSize)
+	for _, field := range schema.Fields {
+		types := make([]string, 0, len(field.Types))
+		for _, t := range field.Types {
+			types = append(types, fmt.Sprintf("%s %.1f%%", t.Type, t.Percent))
+		}
+		fmt.Fprintf(&content, "\n[%s]%s[%s] present in %.1f%% (%d)\n", primary, tview.Escape(field.Path),
+			secondary, field.Presence*100, field.Count)
+		fmt.Fprintf(&content, "  types: %s\n", strings.Join(types, ", "))
+		if field.Min != nil {
+			fmt.Fprintf(&content, "  min: %s, max: %s\n", formatSchemaValue(field.Min), formatSchemaValue(field.Max))
+		}
+		if len(field.TopValues) > 0 {
+			values := make([]string, 0, len(field.TopValues))
+			for _, value := range field.TopValues {
+				text := value.Value
+				if len(text) > 30 {
+					text = text[0:30] + "..."
+				}
+				values = append(values, fmt.Sprintf("%s (%d)", tview.Escape(text), value.Count))
+			}
+			fmt.Fprintf(&content, "  top values: %s\n", strings.Join(values, ", "))
+		}
+	}
+
+	s.SetTitle(fmt.Sprintf(" Schema of %s.%s ", db, coll))
+	s.SetText(primitives.Text{
+		Content: content.String(),
+		Align:   tview.AlignLeft,
+	})
+	s.MoveToTop()
+
+	s.App.Pages.AddPage(s.GetIdentifier(), s, true, true)
+}
+
+// formatSchemaValue formats min and max values, numbers are
+// shown without trailing zeros
+func formatSchemaValue(value interface{}) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return tview.Escape(util.GetValueByType(value))
+}
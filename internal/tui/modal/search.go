@@ -0,0 +1,128 @@
+package modal
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+)
+
+const (
+	SearchModal = "Search"
+)
+
+// Search is a modal with input for the pattern searched in loaded documents
+type Search struct {
+	*core.BaseElement
+	*primitives.InputModal
+
+	backward bool
+	regex    bool
+	onSearch func(search *util.TextSearch, backward bool)
+}
+
+func NewSearchModal() *Search {
+	s := &Search{
+		BaseElement: core.NewBaseElement(),
+		InputModal:  primitives.NewInputModal(),
+	}
+
+	s.SetIdentifier(SearchModal)
+	s.SetAfterInitFunc(s.init)
+
+	return s
+}
+
+func (s *Search) init() error {
+	s.setStaticLayout()
+	s.setStyle()
+	s.setKeybindings()
+
+	s.handleEvents()
+
+	return nil
+}
+
+func (s *Search) setStaticLayout() {
+	s.SetBorder(true)
+	s.SetTitle("Search")
+}
+
+func (s *Search) setStyle() {
+	styles := s.App.GetStyles()
+	s.SetBorderColor(styles.Global.BorderColor.Color())
+	s.SetBackgroundColor(styles.Global.BackgroundColor.Color())
+	s.SetFieldTextColor(styles.Others.ModalTextColor.Color())
+	s.SetFieldBackgroundColor(styles.Global.ContrastBackgroundColor.Color())
+}
+
+func (s *Search) setKeybindings() {
+	keys := s.App.GetKeys()
+	s.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case keys.Contains(keys.Search.AcceptSearch, event.Name()):
+			s.accept()
+			return nil
+		case keys.Contains(keys.Search.ToggleRegex, event.Name()):
+			s.regex = !s.regex
+			s.setLabel()
+			return nil
+		case keys.Contains(keys.Search.CloseSearch, event.Name()):
+			s.App.Pages.RemovePage(s.GetIdentifier())
+			return nil
+		}
+		return event
+	})
+}
+
+func (s *Search) handleEvents() {
+	go s.HandleEvents(s.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			s.setStyle()
+		}
+	})
+}
+
+// Render shows the modal with the previous search, onSearch is called with
+// compiled search or nil if the pattern was cleared
+func (s *Search) Render(previous *util.TextSearch, backward bool, onSearch func(search *util.TextSearch, backward bool)) {
+	s.backward = backward
+	s.onSearch = onSearch
+	s.SetText("")
+	if previous != nil {
+		s.SetText(previous.Pattern)
+		s.regex = previous.Regex
+	}
+	s.setLabel()
+
+	s.App.Pages.AddPage(s.GetIdentifier(), s, true, true)
+}
+
+func (s *Search) setLabel() {
+	label := "Search forward"
+	if s.backward {
+		label = "Search backward"
+	}
+	if s.regex {
+		label += " (regex)"
+	}
+	s.SetLabel(label + ", " + s.App.GetKeys().Search.ToggleRegex.String() + " to toggle regex")
+}
+
+func (s *Search) accept() {
+	pattern := s.GetText()
+	if pattern == "" {
+		s.App.Pages.RemovePage(s.GetIdentifier())
+		s.onSearch(nil, s.backward)
+		return
+	}
+	search, err := util.NewTextSearch(pattern, s.regex)
+	if err != nil {
+		ShowError(s.App.Pages, "Invalid search pattern", err)
+		return
+	}
+	s.App.Pages.RemovePage(s.GetIdentifier())
+	s.onSearch(search, s.backward)
+}
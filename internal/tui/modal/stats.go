@@ -0,0 +1,132 @@
+package modal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+)
+
+const (
+	StatsModal = "StatsModal"
+)
+
+// Stats is a modal with statistics of a database or a collection
+type Stats struct {
+	*core.BaseElement
+	*primitives.ViewModal
+}
+
+func NewStatsModal() *Stats {
+	s := &Stats{
+		BaseElement: core.NewBaseElement(),
+		ViewModal:   primitives.NewViewModal(),
+	}
+
+	s.SetIdentifier(StatsModal)
+	s.SetAfterInitFunc(s.init)
+
+	return s
+}
+
+func (s *Stats) init() error {
+	s.setStaticLayout()
+	s.setStyle()
+
+	s.handleEvents()
+
+	return nil
+}
+
+func (s *Stats) setStaticLayout() {
+	s.SetBorder(true)
+	s.SetTitleAlign(tview.AlignLeft)
+	s.AddButtons([]string{"Close"})
+	s.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		s.App.Pages.RemovePage(s.GetIdentifier())
+	})
+}
+
+func (s *Stats) setStyle() {
+	styles := s.App.GetStyles()
+	s.ViewModal.SetBackgroundColor(styles.Global.BackgroundColor.Color())
+	s.ViewModal.SetTextColor(styles.Global.TextColor.Color())
+	s.ViewModal.SetBorderColor(styles.Global.BorderColor.Color())
+	s.ViewModal.SetButtonBackgroundColor(styles.Global.BackgroundColor.Color())
+	s.ViewModal.SetButtonTextColor(styles.Global.TextColor.Color())
+	s.SetHighlightColor(styles.DocPeeker.HighlightColor.Color())
+}
+
+func (s *Stats) handleEvents() {
+	go s.HandleEvents(s.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			s.setStyle()
+		}
+	})
+}
+
+// RenderCollection shows statistics of the collection
+func (s *Stats) RenderCollection(db, coll string, stats *mongo.CollectionStats) {
+	ttl := "no"
+	if len(stats.TTLIndexes) > 0 {
+		ttl = strings.Join(stats.TTLIndexes, ", ")
+	}
+
+	s.render(fmt.Sprintf(" Statistics of %s.%s ", db, coll), [][2]string{
+		{"Documents", fmt.Sprintf("%d", stats.Count)},
+		{"Average document size", util.FormatBytes(stats.AvgObjSize)},
+		{"Data size", util.FormatBytes(stats.Size)},
+		{"Storage size", util.FormatBytes(stats.StorageSize)},
+		{"Indexes", fmt.Sprintf("%d", stats.Indexes)},
+		{"Total index size", util.FormatBytes(stats.TotalIndexSize)},
+		{"Capped", formatFlag(stats.Capped)},
+		{"Time series", formatFlag(stats.TimeSeries)},
+		{"TTL indexes", tview.Escape(ttl)},
+	})
+}
+
+// RenderDatabase shows statistics of the database
+func (s *Stats) RenderDatabase(db string, stats *mongo.DatabaseStats) {
+	s.render(fmt.Sprintf(" Statistics of %s ", db), [][2]string{
+		{"Collections", fmt.Sprintf("%d", stats.Collections)},
+		{"Views", fmt.Sprintf("%d", stats.Views)},
+		{"Documents", fmt.Sprintf("%d", stats.Objects)},
+		{"Average document size", util.FormatBytes(stats.AvgObjSize)},
+		{"Data size", util.FormatBytes(stats.DataSize)},
+		{"Storage size", util.FormatBytes(stats.StorageSize)},
+		{"Indexes", fmt.Sprintf("%d", stats.Indexes)},
+		{"Index size", util.FormatBytes(stats.IndexSize)},
+	})
+}
+
+func (s *Stats) render(title string, rows [][2]string) {
+	styles := s.App.GetStyles().Others
+	primary, secondary := styles.ModalTextColor.Color(), styles.ModalSecondaryTextColor.Color()
+
+	var content strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&content, "[%s]%s:[%s] %s\n", primary, row[0], secondary, row[1])
+	}
+
+	s.SetTitle(title)
+	s.SetText(primitives.Text{
+		Content: content.String(),
+		Align:   tview.AlignLeft,
+	})
+	s.MoveToTop()
+
+	s.App.Pages.AddPage(s.GetIdentifier(), s, true, true)
+}
+
+func formatFlag(flag bool) string {
+	if flag {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,111 @@
+package modal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/primitives"
+)
+
+const (
+	ValidationModal = "ValidationModal"
+
+	ValidationEditButton     = "Edit"
+	ValidationValidateButton = "Validate"
+	ValidationCloseButton    = "Close"
+)
+
+// Validation is a modal with validation rules of a collection
+type Validation struct {
+	*core.BaseElement
+	*primitives.ViewModal
+
+	actionFunc func(buttonLabel string)
+}
+
+func NewValidationModal() *Validation {
+	v := &Validation{
+		BaseElement: core.NewBaseElement(),
+		ViewModal:   primitives.NewViewModal(),
+	}
+
+	v.SetIdentifier(ValidationModal)
+	v.SetAfterInitFunc(v.init)
+
+	return v
+}
+
+func (v *Validation) init() error {
+	v.setStaticLayout()
+	v.setStyle()
+
+	v.handleEvents()
+
+	return nil
+}
+
+func (v *Validation) setStaticLayout() {
+	v.SetBorder(true)
+	v.SetTitleAlign(tview.AlignLeft)
+	v.AddButtons([]string{ValidationEditButton, ValidationValidateButton, ValidationCloseButton})
+	v.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		v.App.Pages.RemovePage(v.GetIdentifier())
+		if v.actionFunc != nil && buttonLabel != ValidationCloseButton {
+			v.actionFunc(buttonLabel)
+		}
+	})
+}
+
+func (v *Validation) setStyle() {
+	styles := v.App.GetStyles()
+	v.ViewModal.SetBackgroundColor(styles.Global.BackgroundColor.Color())
+	v.ViewModal.SetTextColor(styles.Global.TextColor.Color())
+	v.ViewModal.SetBorderColor(styles.Global.BorderColor.Color())
+	v.ViewModal.SetButtonBackgroundColor(styles.Global.BackgroundColor.Color())
+	v.ViewModal.SetButtonTextColor(styles.Global.TextColor.Color())
+	v.SetHighlightColor(styles.DocPeeker.HighlightColor.Color())
+}
+
+func (v *Validation) handleEvents() {
+	go v.HandleEvents(v.GetIdentifier(), func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			v.setStyle()
+		}
+	})
+}
+
+// Render shows validation rules of the collection, actionFunc is called
+// with the label of pressed button after the modal is closed
+func (v *Validation) Render(db, coll string, validation *mongo.CollectionValidation, actionFunc func(buttonLabel string)) error {
+	validator, err := validation.ToJson()
+	if err != nil {
+		return err
+	}
+	styles := v.App.GetStyles().Others
+	primary, secondary := styles.ModalTextColor.Color(), styles.ModalSecondaryTextColor.Color()
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "[%s]Validation level:[%s] %s\n", primary, secondary, validation.Level)
+	fmt.Fprintf(&content, "[%s]Validation action:[%s] %s\n", primary, secondary, validation.Action)
+	if len(validation.Validator) == 0 {
+		fmt.Fprintf(&content, "\n[%s]Collection has no validator[-]\n", primary)
+	} else {
+		fmt.Fprintf(&content, "\n[%s]Rules:[-]\n%s\n", primary, tview.Escape(validator))
+	}
+
+	v.actionFunc = actionFunc
+	v.SetTitle(fmt.Sprintf(" Validation of %s.%s ", db, coll))
+	v.SetText(primitives.Text{
+		Content: content.String(),
+		Align:   tview.AlignLeft,
+	})
+	v.MoveToTop()
+
+	v.App.Pages.AddPage(v.GetIdentifier(), v, true, true)
+	return nil
+}
@@ -0,0 +1,236 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DashboardPage = "Dashboard"
+
+	// maxHistory is the number of samples kept for sparklines
+	maxHistory = 60
+	// sparklineWidth is the number of samples shown in sparklines
+	sparklineWidth = 40
+)
+
+// Dashboard is a page with server status that is refreshed periodically
+type Dashboard struct {
+	*core.BaseElement
+	*core.TextView
+
+	refresher core.Refresher
+	prev      *mongo.ServerStatus
+	history   map[string][]float64
+}
+
+// dashboardSample is a single refresh of the dashboard
+type dashboardSample struct {
+	status  *mongo.ServerStatus
+	rates   mongo.StatusRates
+	members []mongo.ReplicationMember
+	err     error
+}
+
+func NewDashboard() *Dashboard {
+	d := &Dashboard{
+		BaseElement: core.NewBaseElement(),
+		TextView:    core.NewTextView(),
+	}
+
+	d.SetIdentifier(DashboardPage)
+	d.SetAfterInitFunc(d.init)
+
+	return d
+}
+
+func (d *Dashboard) init() error {
+	d.setStaticLayout()
+	d.setStyle()
+	d.setKeybindings()
+
+	d.handleEvents()
+
+	return nil
+}
+
+func (d *Dashboard) setStaticLayout() {
+	d.SetBorder(true)
+	d.SetTitle(" Server status ")
+	d.SetTitleAlign(tview.AlignLeft)
+	d.SetBorderPadding(1, 1, 2, 2)
+	d.SetDynamicColors(true)
+}
+
+func (d *Dashboard) setStyle() {
+	d.TextView.SetStyle(d.App.GetStyles())
+	d.SetTextColor(d.App.GetStyles().Global.TextColor.Color())
+}
+
+func (d *Dashboard) setKeybindings() {
+	k := d.App.GetKeys()
+	d.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Dashboard.CloseDashboard, event.Name()):
+			d.Close()
+			return nil
+		}
+		return event
+	})
+}
+
+func (d *Dashboard) handleEvents() {
+	go d.HandleEvents(DashboardPage, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			d.setStyle()
+		}
+	})
+}
+
+// Render shows the dashboard and starts refreshing it
+// every interval configured in dashboard settings
+func (d *Dashboard) Render() {
+	d.prev = nil
+	d.history = map[string][]float64{}
+	d.SetText("Loading server status...")
+
+	d.App.Pages.AddPage(DashboardPage, d, true, true)
+
+	interval := time.Duration(d.App.GetConfig().Dashboard.RefreshInterval) * time.Second
+	d.refresher.Start(d.App, interval, func(ctx context.Context) func() {
+		sample := d.fetch(ctx)
+		return func() {
+			d.update(sample)
+		}
+	})
+}
+
+// Close stops refreshing and hides the dashboard
+func (d *Dashboard) Close() {
+	d.refresher.Stop()
+	d.App.Pages.RemovePage(DashboardPage)
+}
+
+func (d *Dashboard) fetch(ctx context.Context) dashboardSample {
+	status, err := d.Dao.GetServerStatus(ctx)
+	if err != nil {
+		return dashboardSample{err: err}
+	}
+	members, err := d.Dao.GetReplicationMembers(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting replication status")
+	}
+	return dashboardSample{status: status, members: members}
+}
+
+// update computes rates from the previous sample, adds values to
+// the history and renders the dashboard, it runs on the main goroutine
+func (d *Dashboard) update(sample dashboardSample) {
+	if sample.err != nil {
+		d.SetText(fmt.Sprintf("Error getting server status: %s", tview.Escape(sample.err.Error())))
+		return
+	}
+	if d.prev != nil {
+		sample.rates = mongo.ComputeRates(d.prev, sample.status)
+	}
+	d.prev = sample.status
+
+	status := sample.status
+	d.record("insert", sample.rates.Insert)
+	d.record("query", sample.rates.Query)
+	d.record("update", sample.rates.Update)
+	d.record("delete", sample.rates.Delete)
+	d.record("getmore", sample.rates.GetMore)
+	d.record("command", sample.rates.Command)
+	d.record("connections", float64(status.Connections.Current))
+	d.record("resident", float64(status.Mem.Resident))
+	d.record("bytesIn", sample.rates.BytesIn)
+	d.record("bytesOut", sample.rates.BytesOut)
+	d.record("cache", float64(status.WiredTiger.Cache.BytesInCache))
+
+	d.SetText(d.render(sample))
+}
+
+func (d *Dashboard) record(metric string, value float64) {
+	history := append(d.history[metric], value)
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	d.history[metric] = history
+}
+
+func (d *Dashboard) render(sample dashboardSample) string {
+	styles := d.App.GetStyles().Others
+	primary, secondary := styles.ModalTextColor.Color(), styles.ModalSecondaryTextColor.Color()
+	status := sample.status
+
+	var b strings.Builder
+	section := func(title string) {
+		fmt.Fprintf(&b, "\n[%s::b]%s[-::-]\n", primary, title)
+	}
+	row := func(label, value, metric string) {
+		fmt.Fprintf(&b, "  [%s]%-14s[-] %-24s [%s]%s[-]\n", primary, label, value, secondary,
+			util.Sparkline(d.history[metric], sparklineWidth))
+	}
+
+	role := "standalone"
+	if status.Repl.SetName != "" {
+		role = "secondary of " + status.Repl.SetName
+		if status.Repl.IsMaster {
+			role = "primary of " + status.Repl.SetName
+		}
+	}
+	fmt.Fprintf(&b, "[%s]Host:[-] %s  [%s]Version:[-] %s  [%s]Uptime:[-] %s  [%s]Role:[-] %s\n",
+		primary, status.Host, primary, status.Version, primary,
+		time.Duration(status.Uptime)*time.Second, primary, role)
+
+	section("Operations per second")
+	row("insert", fmt.Sprintf("%.1f", sample.rates.Insert), "insert")
+	row("query", fmt.Sprintf("%.1f", sample.rates.Query), "query")
+	row("update", fmt.Sprintf("%.1f", sample.rates.Update), "update")
+	row("delete", fmt.Sprintf("%.1f", sample.rates.Delete), "delete")
+	row("getmore", fmt.Sprintf("%.1f", sample.rates.GetMore), "getmore")
+	row("command", fmt.Sprintf("%.1f", sample.rates.Command), "command")
+
+	section("Connections")
+	row("current", fmt.Sprintf("%d (%d available)", status.Connections.Current, status.Connections.Available), "connections")
+
+	section("Memory")
+	row("resident", fmt.Sprintf("%d MB (%d MB virtual)", status.Mem.Resident, status.Mem.Virtual), "resident")
+
+	section("Network per second")
+	row("in", util.FormatBytes(int64(sample.rates.BytesIn)), "bytesIn")
+	row("out", util.FormatBytes(int64(sample.rates.BytesOut)), "bytesOut")
+
+	cache := status.WiredTiger.Cache
+	if cache.MaxBytes > 0 {
+		section("WiredTiger cache")
+		row("used", fmt.Sprintf("%s of %s (%.1f%%)", util.FormatBytes(cache.BytesInCache), util.FormatBytes(cache.MaxBytes),
+			float64(cache.BytesInCache)/float64(cache.MaxBytes)*100), "cache")
+		fmt.Fprintf(&b, "  [%s]%-14s[-] %s\n", primary, "dirty", util.FormatBytes(cache.DirtyBytes))
+	}
+
+	if len(sample.members) > 0 {
+		section("Replication")
+		for _, member := range sample.members {
+			lag := ""
+			if member.State == "SECONDARY" {
+				lag = fmt.Sprintf("lag %s", member.Lag)
+			}
+			fmt.Fprintf(&b, "  [%s]%-24s[-] %-10s %s\n", primary, tview.Escape(member.Name), member.State, lag)
+		}
+	}
+
+	return b.String()
+}
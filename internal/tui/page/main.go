@@ -2,7 +2,8 @@ package page
 
 import (
 	"context"
-	"time"
+	"fmt"
+	"strconv"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/kopecmaciej/tview"
@@ -12,11 +13,12 @@ import (
 	"github.com/kopecmaciej/vi-mongo/internal/tui/component"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
 	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
-	"github.com/rs/zerolog/log"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
 )
 
 const (
-	MainPage = "Main"
+	MainPage        = "Main"
+	MainDeleteModal = "MainDeleteModal"
 )
 
 type Main struct {
@@ -28,6 +30,16 @@ type Main struct {
 	header    *component.Header
 	databases *component.Database
 	content   *component.Content
+
+	commandBar  *component.CommandBar
+	deleteModal *modal.Delete
+	dashboard   *Dashboard
+	operations  *Operations
+	profiler    *Profiler
+	topology    *Topology
+	users       *Users
+	// commandDb is the database selected with :use command
+	commandDb string
 }
 
 func NewMain() *Main {
@@ -38,6 +50,13 @@ func NewMain() *Main {
 		header:      component.NewHeader(),
 		databases:   component.NewDatabase(),
 		content:     component.NewContent(),
+		commandBar:  component.NewCommandBar(),
+		deleteModal: modal.NewDeleteModal(MainDeleteModal),
+		dashboard:   NewDashboard(),
+		operations:  NewOperations(),
+		profiler:    NewProfiler(),
+		topology:    NewTopology(),
+		users:       NewUsers(),
 	}
 
 	m.SetIdentifier(MainPage)
@@ -76,15 +95,24 @@ func (m *Main) Render() {
 	m.header.Render()
 
 	m.databases.SetSelectFunc(m.content.HandleDatabaseSelection)
+	m.databases.DbTree.SetShowProfilerFunc(m.profiler.Render)
+	m.databases.DbTree.SetShowUsersFunc(m.users.Render)
+	m.profiler.SetOpenQueryFunc(m.content.OpenWithQuery)
 
 	m.render()
 }
 
 // UpdateDao updates the dao in the components
 func (m *Main) UpdateDao(dao *mongo.Dao) {
+	m.BaseElement.UpdateDao(dao)
 	m.databases.UpdateDao(dao)
 	m.header.UpdateDao(dao)
 	m.content.UpdateDao(dao)
+	m.dashboard.UpdateDao(dao)
+	m.operations.UpdateDao(dao)
+	m.profiler.UpdateDao(dao)
+	m.topology.UpdateDao(dao)
+	m.users.UpdateDao(dao)
 }
 
 func (m *Main) initComponents() error {
@@ -97,6 +125,35 @@ func (m *Main) initComponents() error {
 	if err := m.content.Init(m.App); err != nil {
 		return err
 	}
+	if err := m.dashboard.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.operations.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.profiler.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.topology.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.users.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.commandBar.Init(m.App); err != nil {
+		return err
+	}
+	if err := m.deleteModal.Init(m.App); err != nil {
+		return err
+	}
+
+	m.commandBar.SetCompleteFunc(m.completeCommand)
+	m.commandBar.SetExecuteFunc(m.executeCommand)
+	m.commandBar.SetCloseFunc(func() {
+		m.innerFlex.RemoveItem(m.commandBar)
+		m.App.GiveBackFocus()
+	})
+
 	return nil
 }
 
@@ -118,6 +175,10 @@ func (m *Main) render() error {
 func (m *Main) setKeybindings() {
 	k := m.App.GetKeys()
 	m.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// all keys are handled by the command bar when it's open
+		if m.commandBar.IsEnabled() {
+			return event
+		}
 		switch {
 		case k.Contains(k.Main.ToggleFocus, event.Name()):
 			if m.App.GetFocus() == m.databases.DbTree {
@@ -142,27 +203,162 @@ func (m *Main) setKeybindings() {
 			}
 			return nil
 		case k.Contains(k.Main.ShowServerInfo, event.Name()):
-			m.ShowServerInfoModal()
+			m.dashboard.Render()
+			return nil
+		case k.Contains(k.Main.ShowOperations, event.Name()):
+			m.operations.Render()
+			return nil
+		case k.Contains(k.Main.ShowTopology, event.Name()):
+			m.topology.Render()
+			return nil
+		case k.Contains(k.Main.ShowCommandBar, event.Name()):
+			// colon is a part of queries typed in input bars
+			if _, ok := m.App.GetFocus().(*component.InputBar); ok {
+				return event
+			}
+			m.showCommandBar()
 			return nil
 		}
 		return event
 	})
 }
 
-func (m *Main) ShowServerInfoModal() {
-	serverInfoModal := modal.NewServerInfoModal(m.Dao)
-	if err := serverInfoModal.Init(m.App); err != nil {
-		log.Error().Err(err).Msg("Failed to initialize server info modal")
-		return
+func (m *Main) showCommandBar() {
+	m.commandBar.Open()
+	m.innerFlex.AddItem(m.commandBar, 1, 0, false)
+	m.App.SetFocus(m.commandBar)
+}
+
+// completeCommand completes command names, databases and collections of
+// the database selected with :use or the one that is displayed
+func (m *Main) completeCommand(line string) []string {
+	db := m.commandDb
+	if db == "" {
+		db, _ = m.content.GetCollection()
+	}
+
+	dbs, colls := []string{}, []string{}
+	for _, dbWithColls := range m.databases.GetDbsWithCollections() {
+		dbs = append(dbs, dbWithColls.DB)
+		if dbWithColls.DB == db {
+			colls, _ = m.databases.GetCollections(context.Background(), db)
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	return util.CompleteCommand(line, dbs, colls)
+}
 
-	if err := serverInfoModal.Render(ctx); err != nil {
-		log.Error().Err(err).Msg("Failed to render server info modal")
+func (m *Main) executeCommand(line string) {
+	command, err := util.ParseCommand(line)
+	if err != nil {
+		modal.ShowError(m.App.Pages, "Invalid command", err)
 		return
 	}
 
-	m.App.Pages.AddPage(modal.ServerInfoModalView, serverInfoModal, true, true)
+	ctx := context.Background()
+	switch command.Name {
+	case util.CommandQuit:
+		m.App.Stop()
+	case util.CommandUse:
+		err = m.useDatabase(command.Args)
+	case util.CommandColl:
+		err = m.openCollection(ctx, command.Args)
+	default:
+		err = m.executeCollectionCommand(ctx, command)
+	}
+
+	if err != nil {
+		modal.ShowError(m.App.Pages, "Error executing :"+command.Name, err)
+	}
+}
+
+func (m *Main) useDatabase(db string) error {
+	if db == "" {
+		return fmt.Errorf("database name is required")
+	}
+	for _, dbWithColls := range m.databases.GetDbsWithCollections() {
+		if dbWithColls.DB == db {
+			m.commandDb = db
+			return nil
+		}
+	}
+	return fmt.Errorf("database %s not found", db)
+}
+
+func (m *Main) openCollection(ctx context.Context, coll string) error {
+	if coll == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	db := m.commandDb
+	if db == "" {
+		db, _ = m.content.GetCollection()
+	}
+	if db == "" {
+		return fmt.Errorf("no database selected, use :use <db> first")
+	}
+	colls, err := m.databases.GetCollections(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, c := range colls {
+		if c == coll {
+			return m.content.HandleDatabaseSelection(ctx, db, coll)
+		}
+	}
+	return fmt.Errorf("collection %s not found in %s", coll, db)
+}
+
+// executeCollectionCommand executes commands that work on displayed collection
+func (m *Main) executeCollectionCommand(ctx context.Context, command util.Command) error {
+	db, coll := m.content.GetCollection()
+	if coll == "" {
+		return fmt.Errorf("no collection selected")
+	}
+
+	switch command.Name {
+	case util.CommandFind:
+		return m.content.ApplyFilter(ctx, command.Args)
+	case util.CommandSort:
+		return m.content.ApplySort(ctx, command.Args)
+	case util.CommandLimit, util.CommandPage:
+		number, err := strconv.ParseInt(command.Args, 10, 64)
+		if err != nil || number < 1 {
+			return fmt.Errorf("positive number is required, got: %q", command.Args)
+		}
+		if command.Name == util.CommandLimit {
+			return m.content.SetLimit(ctx, number)
+		}
+		return m.content.SetPage(ctx, number)
+	case util.CommandExport:
+		if command.Args == "" {
+			return fmt.Errorf("file path is required")
+		}
+		count, err := m.content.ExportDocuments(ctx, command.Args)
+		if err != nil {
+			return err
+		}
+		modal.ShowInfo(m.App.Pages, fmt.Sprintf("Exported %d documents to %s", count, command.Args))
+	case util.CommandDrop:
+		m.showDropModal(ctx, db, coll)
+	}
+	return nil
+}
+
+func (m *Main) showDropModal(ctx context.Context, db, coll string) {
+	m.deleteModal.SetText(fmt.Sprintf("Are you sure you want to drop collection [::b]%s[::-] from [::b]%s[::-]", coll, db))
+	m.deleteModal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		m.App.Pages.RemovePage(MainDeleteModal)
+		if buttonLabel != "Delete" {
+			return
+		}
+		if err := m.Dao.DeleteCollection(ctx, db, coll); err != nil {
+			modal.ShowError(m.App.Pages, "Error dropping collection", err)
+			return
+		}
+		m.databases.Render()
+		if err := m.content.Refresh(ctx); err != nil {
+			modal.ShowError(m.App.Pages, "Error refreshing documents", err)
+		}
+	})
+	m.App.Pages.AddPage(MainDeleteModal, m.deleteModal, true, true)
 }
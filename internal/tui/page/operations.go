@@ -0,0 +1,243 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
+)
+
+const (
+	OperationsPage        = "Operations"
+	OperationsFilterModal = "OperationsFilterModal"
+	OperationsKillModal   = "OperationsKillModal"
+
+	// maxCommandWidth is the number of characters of the command shown in the table
+	maxCommandWidth = 80
+)
+
+// Operations is a page with in-progress operations that is refreshed periodically
+type Operations struct {
+	*core.BaseElement
+	*core.Table
+
+	filterModal *modal.Form
+	killModal   *modal.Confirm
+
+	refresher  core.Refresher
+	operations []mongo.Operation
+	namespace  string
+	minSecs    int64
+}
+
+func NewOperations() *Operations {
+	o := &Operations{
+		BaseElement: core.NewBaseElement(),
+		Table:       core.NewTable(),
+		filterModal: modal.NewFormModal(OperationsFilterModal),
+		killModal:   modal.NewConfirmModal(OperationsKillModal),
+	}
+
+	o.SetIdentifier(OperationsPage)
+	o.SetAfterInitFunc(o.init)
+
+	return o
+}
+
+func (o *Operations) init() error {
+	o.setStaticLayout()
+	o.setStyle()
+	o.setKeybindings()
+
+	if err := o.filterModal.Init(o.App); err != nil {
+		return err
+	}
+	if err := o.killModal.Init(o.App); err != nil {
+		return err
+	}
+
+	o.handleEvents()
+
+	return nil
+}
+
+func (o *Operations) setStaticLayout() {
+	o.SetBorder(true)
+	o.SetTitleAlign(tview.AlignLeft)
+	o.SetBorderPadding(0, 0, 1, 1)
+	o.SetSelectable(true, false)
+	o.SetFixed(1, 0)
+}
+
+func (o *Operations) setStyle() {
+	styles := o.App.GetStyles()
+	o.Table.SetStyle(styles)
+	o.SetSelectedStyle(tcell.StyleDefault.
+		Foreground(styles.Content.SelectedRowColor.Color()).
+		Background(styles.Global.ContrastBackgroundColor.Color()))
+}
+
+func (o *Operations) setKeybindings() {
+	k := o.App.GetKeys()
+	o.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Operations.FilterOperations, event.Name()):
+			o.showFilterModal()
+			return nil
+		case k.Contains(k.Operations.KillOperation, event.Name()):
+			o.showKillModal()
+			return nil
+		case k.Contains(k.Operations.CloseOperations, event.Name()):
+			o.Close()
+			return nil
+		}
+		return event
+	})
+}
+
+func (o *Operations) handleEvents() {
+	go o.HandleEvents(OperationsPage, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			o.setStyle()
+		}
+	})
+}
+
+// Render shows the page and starts refreshing operations
+// every interval configured in operations settings
+func (o *Operations) Render() {
+	o.operations = nil
+	o.renderTable()
+
+	o.App.Pages.AddPage(OperationsPage, o, true, true)
+
+	interval := time.Duration(o.App.GetConfig().Operations.RefreshInterval) * time.Second
+	o.refresher.Start(o.App, interval, func(ctx context.Context) func() {
+		operations, err := o.Dao.GetCurrentOperations(ctx)
+		return func() {
+			if err != nil {
+				o.SetTitle(fmt.Sprintf(" Operations - error: %s ", err))
+				return
+			}
+			o.operations = operations
+			o.renderTable()
+		}
+	})
+}
+
+// Close stops refreshing and hides the page
+func (o *Operations) Close() {
+	o.refresher.Stop()
+	o.App.Pages.RemovePage(OperationsPage)
+}
+
+// renderTable shows filtered operations keeping the selected one
+func (o *Operations) renderTable() {
+	selectedID := o.selectedOpID()
+	filtered := mongo.FilterOperations(o.operations, o.namespace, o.minSecs)
+
+	o.Clear()
+	headerColor := o.App.GetStyles().Content.ColumnKeyColor.Color()
+	for col, header := range []string{"OpID", "Namespace", "Op", "Secs", "Client", "Plan", "Command"} {
+		o.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(headerColor).
+			SetSelectable(false))
+	}
+
+	selectedRow := 1
+	for i, op := range filtered {
+		row := i + 1
+		command := op.Command
+		if len(command) > maxCommandWidth {
+			command = command[:maxCommandWidth] + "..."
+		}
+		values := []string{fmt.Sprintf("%v", op.OpID), op.Namespace, op.Type, strconv.FormatInt(op.SecsRunning, 10),
+			op.Client, op.PlanSummary, command}
+		for col, value := range values {
+			o.SetCell(row, col, tview.NewTableCell(tview.Escape(value)).SetReference(op))
+		}
+		if fmt.Sprintf("%v", op.OpID) == selectedID {
+			selectedRow = row
+		}
+	}
+	if len(filtered) > 0 {
+		o.Select(selectedRow, 0)
+	}
+
+	o.SetTitle(o.title(len(filtered)))
+}
+
+func (o *Operations) title(count int) string {
+	filters := []string{}
+	if o.namespace != "" {
+		filters = append(filters, "namespace: "+o.namespace)
+	}
+	if o.minSecs > 0 {
+		filters = append(filters, fmt.Sprintf("running >= %ds", o.minSecs))
+	}
+	title := fmt.Sprintf(" Operations (%d) ", count)
+	if len(filters) > 0 {
+		title += "| " + strings.Join(filters, ", ") + " "
+	}
+	return title
+}
+
+func (o *Operations) selectedOperation() (mongo.Operation, bool) {
+	row, _ := o.GetSelection()
+	op, ok := o.GetCell(row, 0).GetReference().(mongo.Operation)
+	return op, ok
+}
+
+func (o *Operations) selectedOpID() string {
+	if op, ok := o.selectedOperation(); ok {
+		return fmt.Sprintf("%v", op.OpID)
+	}
+	return ""
+}
+
+func (o *Operations) showFilterModal() {
+	o.filterModal.Render("Filter operations", "Apply", func(form *tview.Form) {
+		form.AddInputField("Namespace", o.namespace, 40, nil, nil)
+		form.AddInputField("Minimum seconds", strconv.FormatInt(o.minSecs, 10), 10, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) error {
+		minSecs := int64(0)
+		if text := form.GetFormItemByLabel("Minimum seconds").(*tview.InputField).GetText(); text != "" {
+			var err error
+			if minSecs, err = strconv.ParseInt(text, 10, 64); err != nil {
+				return fmt.Errorf("minimum seconds must be a number")
+			}
+		}
+		o.namespace = strings.TrimSpace(form.GetFormItemByLabel("Namespace").(*tview.InputField).GetText())
+		o.minSecs = minSecs
+		o.renderTable()
+		return nil
+	})
+}
+
+func (o *Operations) showKillModal() {
+	op, ok := o.selectedOperation()
+	if !ok {
+		return
+	}
+
+	content := fmt.Sprintf("Kill operation %v on %s running for %ds?\n\n%s", op.OpID, op.Namespace, op.SecsRunning, op.Command)
+	o.killModal.Render("Kill operation", content, []string{"Kill", "Cancel"}, func(buttonLabel string) {
+		if buttonLabel != "Kill" {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := o.Dao.KillOperation(ctx, op.OpID); err != nil {
+			modal.ShowError(o.App.Pages, "Error killing operation", err)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
+)
+
+const (
+	ProfilerPage       = "Profiler"
+	ProfilerLevelModal = "ProfilerLevelModal"
+
+	// maxProfileEntries is the number of the most recent entries shown
+	maxProfileEntries = 100
+)
+
+var profilingLevels = []string{"0 - off", "1 - slow operations", "2 - all operations"}
+
+// Profiler is a page with profiled operations of a database
+type Profiler struct {
+	*core.BaseElement
+	*core.Table
+
+	levelModal *modal.Form
+
+	db            string
+	status        *mongo.ProfilingStatus
+	openQueryFunc func(ctx context.Context, db, coll, filter string) error
+}
+
+func NewProfiler() *Profiler {
+	p := &Profiler{
+		BaseElement: core.NewBaseElement(),
+		Table:       core.NewTable(),
+		levelModal:  modal.NewFormModal(ProfilerLevelModal),
+	}
+
+	p.SetIdentifier(ProfilerPage)
+	p.SetAfterInitFunc(p.init)
+
+	return p
+}
+
+func (p *Profiler) init() error {
+	p.setStaticLayout()
+	p.setStyle()
+	p.setKeybindings()
+
+	if err := p.levelModal.Init(p.App); err != nil {
+		return err
+	}
+
+	p.handleEvents()
+
+	return nil
+}
+
+func (p *Profiler) setStaticLayout() {
+	p.SetBorder(true)
+	p.SetTitleAlign(tview.AlignLeft)
+	p.SetBorderPadding(0, 0, 1, 1)
+	p.SetSelectable(true, false)
+	p.SetFixed(1, 0)
+}
+
+func (p *Profiler) setStyle() {
+	styles := p.App.GetStyles()
+	p.Table.SetStyle(styles)
+	p.SetSelectedStyle(tcell.StyleDefault.
+		Foreground(styles.Content.SelectedRowColor.Color()).
+		Background(styles.Global.ContrastBackgroundColor.Color()))
+}
+
+func (p *Profiler) setKeybindings() {
+	k := p.App.GetKeys()
+	p.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Profiler.ChangeLevel, event.Name()):
+			p.showLevelModal()
+			return nil
+		case k.Contains(k.Profiler.Refresh, event.Name()):
+			p.refresh()
+			return nil
+		case k.Contains(k.Profiler.OpenQuery, event.Name()):
+			p.openQuery()
+			return nil
+		case k.Contains(k.Profiler.CloseProfiler, event.Name()):
+			p.App.Pages.RemovePage(ProfilerPage)
+			return nil
+		}
+		return event
+	})
+}
+
+func (p *Profiler) handleEvents() {
+	go p.HandleEvents(ProfilerPage, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			p.setStyle()
+		}
+	})
+}
+
+// SetOpenQueryFunc sets the function that opens the collection
+// of the profiled operation with its filter
+func (p *Profiler) SetOpenQueryFunc(f func(ctx context.Context, db, coll, filter string) error) {
+	p.openQueryFunc = f
+}
+
+// Render shows profiling level and profiled operations of the database
+func (p *Profiler) Render(db string) {
+	p.db = db
+	p.status = nil
+	p.refresh()
+	p.App.Pages.AddPage(ProfilerPage, p, true, true)
+}
+
+func (p *Profiler) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status, err := p.Dao.GetProfilingStatus(ctx, p.db)
+	if err != nil {
+		modal.ShowError(p.App.Pages, "Error getting profiling level", err)
+		return
+	}
+	p.status = status
+
+	entries, err := p.Dao.GetProfileEntries(ctx, p.db, maxProfileEntries)
+	if err != nil {
+		modal.ShowError(p.App.Pages, "Error getting profiled operations", err)
+		return
+	}
+	p.renderTable(entries)
+}
+
+func (p *Profiler) renderTable(entries []mongo.ProfileEntry) {
+	p.Clear()
+	headerColor := p.App.GetStyles().Content.ColumnKeyColor.Color()
+	for col, header := range []string{"Time", "Namespace", "Op", "Millis", "Docs examined", "Keys examined", "Plan", "Filter"} {
+		p.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(headerColor).
+			SetSelectable(false))
+	}
+
+	for i, entry := range entries {
+		values := []string{
+			entry.Time.Format(time.DateTime),
+			entry.Namespace,
+			entry.Op,
+			strconv.FormatInt(entry.Millis, 10),
+			strconv.FormatInt(entry.DocsExamined, 10),
+			strconv.FormatInt(entry.KeysExamined, 10),
+			entry.PlanSummary,
+			entry.Filter,
+		}
+		for col, value := range values {
+			p.SetCell(i+1, col, tview.NewTableCell(tview.Escape(value)).SetReference(entry))
+		}
+	}
+	if len(entries) > 0 {
+		p.Select(1, 0)
+	}
+
+	p.SetTitle(fmt.Sprintf(" Profiler of %s | level: %s, slowms: %d ", p.db, profilingLevels[p.status.Level], p.status.SlowMs))
+}
+
+func (p *Profiler) showLevelModal() {
+	if p.status == nil {
+		return
+	}
+	p.levelModal.Render("Profiling of "+p.db, "Save", func(form *tview.Form) {
+		form.AddDropDown("Level", profilingLevels, int(p.status.Level), nil)
+		form.AddInputField("Slow ms", strconv.FormatInt(p.status.SlowMs, 10), 10, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) error {
+		level, _ := form.GetFormItemByLabel("Level").(*tview.DropDown).GetCurrentOption()
+		slowMs, err := strconv.ParseInt(form.GetFormItemByLabel("Slow ms").(*tview.InputField).GetText(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("slow ms must be a number")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		status := mongo.ProfilingStatus{Level: int64(level), SlowMs: slowMs}
+		if err := p.Dao.SetProfilingStatus(ctx, p.db, status); err != nil {
+			return err
+		}
+		p.refresh()
+		return nil
+	})
+}
+
+// openQuery opens collection of the selected operation
+// with its filter prefilled in the query bar
+func (p *Profiler) openQuery() {
+	row, _ := p.GetSelection()
+	entry, ok := p.GetCell(row, 0).GetReference().(mongo.ProfileEntry)
+	if !ok {
+		return
+	}
+	db, coll := mongo.SplitNamespace(entry.Namespace)
+	if coll == "" || p.openQueryFunc == nil {
+		return
+	}
+
+	p.App.Pages.RemovePage(ProfilerPage)
+	if err := p.openQueryFunc(context.Background(), db, coll, entry.Filter); err != nil {
+		modal.ShowError(p.App.Pages, "Error opening collection", err)
+	}
+}
@@ -0,0 +1,214 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+)
+
+const (
+	TopologyPage = "Topology"
+)
+
+// Topology is a page with replica set members or, when connected
+// to mongos, with shards and chunk distribution, refreshed periodically
+type Topology struct {
+	*core.BaseElement
+	*core.TextView
+
+	refresher core.Refresher
+}
+
+// topologySample is a single refresh of the topology
+type topologySample struct {
+	sharding *mongo.ShardingStatus
+	members  []mongo.ReplicationMember
+	err      error
+}
+
+func NewTopology() *Topology {
+	t := &Topology{
+		BaseElement: core.NewBaseElement(),
+		TextView:    core.NewTextView(),
+	}
+
+	t.SetIdentifier(TopologyPage)
+	t.SetAfterInitFunc(t.init)
+
+	return t
+}
+
+func (t *Topology) init() error {
+	t.setStaticLayout()
+	t.setStyle()
+	t.setKeybindings()
+
+	t.handleEvents()
+
+	return nil
+}
+
+func (t *Topology) setStaticLayout() {
+	t.SetBorder(true)
+	t.SetTitle(" Topology ")
+	t.SetTitleAlign(tview.AlignLeft)
+	t.SetBorderPadding(1, 1, 2, 2)
+	t.SetDynamicColors(true)
+}
+
+func (t *Topology) setStyle() {
+	t.TextView.SetStyle(t.App.GetStyles())
+	t.SetTextColor(t.App.GetStyles().Global.TextColor.Color())
+}
+
+func (t *Topology) setKeybindings() {
+	k := t.App.GetKeys()
+	t.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Topology.CloseTopology, event.Name()):
+			t.Close()
+			return nil
+		}
+		return event
+	})
+}
+
+func (t *Topology) handleEvents() {
+	go t.HandleEvents(TopologyPage, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			t.setStyle()
+		}
+	})
+}
+
+// Render shows the topology and starts refreshing it
+// every interval configured in topology settings
+func (t *Topology) Render() {
+	t.SetText("Loading topology...")
+
+	t.App.Pages.AddPage(TopologyPage, t, true, true)
+
+	interval := time.Duration(t.App.GetConfig().Topology.RefreshInterval) * time.Second
+	t.refresher.Start(t.App, interval, func(ctx context.Context) func() {
+		sample := t.fetch(ctx)
+		return func() {
+			t.SetText(t.render(sample))
+		}
+	})
+}
+
+// Close stops refreshing and hides the topology
+func (t *Topology) Close() {
+	t.refresher.Stop()
+	t.App.Pages.RemovePage(TopologyPage)
+}
+
+func (t *Topology) fetch(ctx context.Context) topologySample {
+	mongos, err := t.Dao.IsMongos(ctx)
+	if err != nil {
+		return topologySample{err: err}
+	}
+	if mongos {
+		sharding, err := t.Dao.GetShardingStatus(ctx)
+		return topologySample{sharding: sharding, err: err}
+	}
+	members, err := t.Dao.GetReplicationMembers(ctx)
+	return topologySample{members: members, err: err}
+}
+
+func (t *Topology) render(sample topologySample) string {
+	if sample.err != nil {
+		return fmt.Sprintf("Error getting topology: %s", tview.Escape(sample.err.Error()))
+	}
+
+	styles := t.App.GetStyles().Others
+	primary, secondary := styles.ModalTextColor.Color(), styles.ModalSecondaryTextColor.Color()
+
+	var b strings.Builder
+	section := func(title string) {
+		fmt.Fprintf(&b, "[%s::b]%s[-::-]\n", primary, title)
+	}
+	header := func(format string, values ...interface{}) {
+		fmt.Fprintf(&b, "  [%s]"+format+"[-]\n", append([]interface{}{secondary}, values...)...)
+	}
+
+	if sample.sharding != nil {
+		sharding := sample.sharding
+		running := "idle"
+		if sharding.Balancer.InRound {
+			running = "balancing"
+		}
+		section("Balancer")
+		fmt.Fprintf(&b, "  mode: %s, %s\n\n", sharding.Balancer.Mode, running)
+
+		section(fmt.Sprintf("Shards (%d)", len(sharding.Shards)))
+		header("%-20s %-10s %s", "ID", "State", "Host")
+		for _, shard := range sharding.Shards {
+			state := "active"
+			if shard.Draining {
+				state = "draining"
+			}
+			fmt.Fprintf(&b, "  %-20s %-10s %s\n", tview.Escape(shard.ID), state, tview.Escape(shard.Host))
+		}
+
+		fmt.Fprintf(&b, "\n")
+		section(fmt.Sprintf("Sharded collections (%d)", len(sharding.Collections)))
+		header("%-40s %-8s %s", "Namespace", "Chunks", "Distribution")
+		for _, coll := range sharding.Collections {
+			total, distribution := chunkDistribution(coll.Chunks)
+			fmt.Fprintf(&b, "  %-40s %-8d %s\n", tview.Escape(coll.Namespace), total, tview.Escape(distribution))
+		}
+		return b.String()
+	}
+
+	if sample.members == nil {
+		return "Server is a standalone instance, it's not a replica set member nor mongos"
+	}
+
+	section(fmt.Sprintf("Replica set members (%d)", len(sample.members)))
+	header("%-24s %-12s %-8s %-20s %-10s %s", "Name", "State", "Health", "Optime", "Lag", "Sync source")
+	for _, member := range sample.members {
+		health := "down"
+		if member.Healthy {
+			health = "up"
+		}
+		optime := ""
+		if !member.Optime.IsZero() {
+			optime = member.Optime.Format(time.DateTime)
+		}
+		lag := ""
+		if member.State == "SECONDARY" {
+			lag = member.Lag.String()
+		}
+		fmt.Fprintf(&b, "  %-24s %-12s %-8s %-20s %-10s %s\n", tview.Escape(member.Name), tview.Escape(member.State),
+			health, optime, lag, tview.Escape(member.SyncSource))
+	}
+	return b.String()
+}
+
+// chunkDistribution returns total number of chunks and
+// their distribution in form of "shard: count" sorted by shard
+func chunkDistribution(chunks map[string]int64) (int64, string) {
+	shards := make([]string, 0, len(chunks))
+	for shard := range chunks {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	total := int64(0)
+	parts := make([]string, 0, len(shards))
+	for _, shard := range shards {
+		total += chunks[shard]
+		parts = append(parts, fmt.Sprintf("%s: %d", shard, chunks[shard]))
+	}
+	return total, strings.Join(parts, ", ")
+}
@@ -0,0 +1,401 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/tview"
+	"github.com/kopecmaciej/vi-mongo/internal/manager"
+	"github.com/kopecmaciej/vi-mongo/internal/mongo"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/core"
+	"github.com/kopecmaciej/vi-mongo/internal/tui/modal"
+)
+
+const (
+	UsersPage         = "Users"
+	UsersFormModal    = "UsersFormModal"
+	UsersConfirmModal = "UsersConfirmModal"
+
+	// noRoleOption is the first option of the role dropdown that picks no role
+	noRoleOption = "(none)"
+)
+
+// Users is a page with users and roles of a database
+type Users struct {
+	*core.BaseElement
+	*core.Flex
+
+	usersTable   *core.Table
+	rolesTable   *core.Table
+	formModal    *modal.Form
+	confirmModal *modal.Confirm
+
+	db    string
+	users []mongo.DbUser
+	roles []mongo.DbRole
+}
+
+func NewUsers() *Users {
+	u := &Users{
+		BaseElement:  core.NewBaseElement(),
+		Flex:         core.NewFlex(),
+		usersTable:   core.NewTable(),
+		rolesTable:   core.NewTable(),
+		formModal:    modal.NewFormModal(UsersFormModal),
+		confirmModal: modal.NewConfirmModal(UsersConfirmModal),
+	}
+
+	u.SetIdentifier(UsersPage)
+	u.SetAfterInitFunc(u.init)
+
+	return u
+}
+
+func (u *Users) init() error {
+	u.setStaticLayout()
+	u.setStyle()
+	u.setKeybindings()
+
+	if err := u.formModal.Init(u.App); err != nil {
+		return err
+	}
+	if err := u.confirmModal.Init(u.App); err != nil {
+		return err
+	}
+
+	u.handleEvents()
+
+	return nil
+}
+
+func (u *Users) setStaticLayout() {
+	for _, table := range []*core.Table{u.usersTable, u.rolesTable} {
+		table.SetBorder(true)
+		table.SetTitleAlign(tview.AlignLeft)
+		table.SetBorderPadding(0, 0, 1, 1)
+		table.SetSelectable(true, false)
+		table.SetFixed(1, 0)
+	}
+
+	u.Flex.SetDirection(tview.FlexRow)
+	u.Flex.AddItem(u.usersTable, 0, 1, true)
+	u.Flex.AddItem(u.rolesTable, 0, 1, false)
+}
+
+func (u *Users) setStyle() {
+	styles := u.App.GetStyles()
+	u.Flex.SetStyle(styles)
+	for _, table := range []*core.Table{u.usersTable, u.rolesTable} {
+		table.SetStyle(styles)
+		table.SetSelectedStyle(tcell.StyleDefault.
+			Foreground(styles.Content.SelectedRowColor.Color()).
+			Background(styles.Global.ContrastBackgroundColor.Color()))
+	}
+}
+
+func (u *Users) setKeybindings() {
+	k := u.App.GetKeys()
+	u.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case k.Contains(k.Users.CreateUser, event.Name()):
+			u.showCreateUserForm()
+			return nil
+		case k.Contains(k.Users.GrantRole, event.Name()):
+			u.showGrantRoleForm()
+			return nil
+		case k.Contains(k.Users.RevokeRole, event.Name()):
+			u.showRevokeRoleForm()
+			return nil
+		case k.Contains(k.Users.ChangePassword, event.Name()):
+			u.showChangePasswordForm()
+			return nil
+		case k.Contains(k.Users.DropUser, event.Name()):
+			u.showDropUserModal()
+			return nil
+		case k.Contains(k.Users.SwitchFocus, event.Name()):
+			if u.usersTable.HasFocus() {
+				u.App.SetFocus(u.rolesTable)
+			} else {
+				u.App.SetFocus(u.usersTable)
+			}
+			return nil
+		case k.Contains(k.Users.Refresh, event.Name()):
+			u.refresh()
+			return nil
+		case k.Contains(k.Users.CloseUsers, event.Name()):
+			u.App.Pages.RemovePage(UsersPage)
+			return nil
+		}
+		return event
+	})
+}
+
+func (u *Users) handleEvents() {
+	go u.HandleEvents(UsersPage, func(event manager.EventMsg) {
+		switch event.Message.Type {
+		case manager.StyleChanged:
+			u.setStyle()
+		}
+	})
+}
+
+// Render shows users and roles of the database
+func (u *Users) Render(db string) {
+	u.db = db
+	u.users, u.roles = nil, nil
+	u.refresh()
+	u.App.Pages.AddPage(UsersPage, u, true, true)
+	u.App.SetFocus(u.usersTable)
+}
+
+func (u *Users) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	users, err := u.Dao.GetUsers(ctx, u.db)
+	if err != nil {
+		modal.ShowError(u.App.Pages, "Error getting users", err)
+		return
+	}
+	roles, err := u.Dao.GetRoles(ctx, u.db)
+	if err != nil {
+		modal.ShowError(u.App.Pages, "Error getting roles", err)
+		return
+	}
+	u.users, u.roles = users, roles
+	u.renderUsers()
+	u.renderRoles()
+}
+
+func (u *Users) renderUsers() {
+	row, _ := u.usersTable.GetSelection()
+
+	u.usersTable.Clear()
+	u.setHeader(u.usersTable, "User", "Roles")
+	for i, user := range u.users {
+		u.usersTable.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(user.Name)).SetReference(user))
+		u.usersTable.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(joinRoles(user.Roles))).SetReference(user))
+	}
+	if len(u.users) > 0 {
+		u.usersTable.Select(min(max(row, 1), len(u.users)), 0)
+	}
+	u.usersTable.SetTitle(fmt.Sprintf(" Users of %s (%d) ", u.db, len(u.users)))
+}
+
+func (u *Users) renderRoles() {
+	u.rolesTable.Clear()
+	u.setHeader(u.rolesTable, "Role", "Type", "Inherits")
+	for i, role := range u.roles {
+		roleType := "custom"
+		if role.BuiltIn {
+			roleType = "built-in"
+		}
+		values := []string{mongo.RoleRef{Role: role.Name, Db: role.Db}.String(), roleType, joinRoles(role.Roles)}
+		for col, value := range values {
+			u.rolesTable.SetCell(i+1, col, tview.NewTableCell(tview.Escape(value)))
+		}
+	}
+	u.rolesTable.SetTitle(fmt.Sprintf(" Roles of %s (%d) ", u.db, len(u.roles)))
+}
+
+func (u *Users) setHeader(table *core.Table, headers ...string) {
+	headerColor := u.App.GetStyles().Content.ColumnKeyColor.Color()
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(headerColor).
+			SetSelectable(false))
+	}
+}
+
+func (u *Users) selectedUser() (mongo.DbUser, bool) {
+	row, _ := u.usersTable.GetSelection()
+	user, ok := u.usersTable.GetCell(row, 0).GetReference().(mongo.DbUser)
+	return user, ok
+}
+
+// roleOptions returns roles of the database that can be picked, the first
+// option picks no role, so users can type roles of other databases only
+func (u *Users) roleOptions(exclude []mongo.RoleRef) []string {
+	excluded := map[string]bool{}
+	for _, role := range exclude {
+		excluded[role.String()] = true
+	}
+	options := []string{noRoleOption}
+	for _, role := range u.roles {
+		option := mongo.RoleRef{Role: role.Name, Db: role.Db}.String()
+		if !excluded[option] {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// confirm shows confirmation after the form is closed and runs the action
+// if it's confirmed, users and roles are refreshed after the action
+func (u *Users) confirm(title, content, button string, action func(ctx context.Context) error) {
+	go u.App.QueueUpdateDraw(func() {
+		u.confirmModal.Render(title, content, []string{button, "Cancel"}, func(buttonLabel string) {
+			if buttonLabel != button {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := action(ctx); err != nil {
+				modal.ShowError(u.App.Pages, title+" failed", err)
+				return
+			}
+			u.refresh()
+		})
+	})
+}
+
+func (u *Users) showCreateUserForm() {
+	u.formModal.Render("Create user in "+u.db, "Create", func(form *tview.Form) {
+		form.AddInputField("Name", "", 40, nil, nil)
+		form.AddPasswordField("Password", "", 40, '*', nil)
+		form.AddDropDown("Role", u.roleOptions(nil), 0, nil)
+		form.AddInputField("Other roles", "", 40, nil, nil)
+	}, func(form *tview.Form) error {
+		name := strings.TrimSpace(form.GetFormItemByLabel("Name").(*tview.InputField).GetText())
+		password := form.GetFormItemByLabel("Password").(*tview.InputField).GetText()
+		if name == "" || password == "" {
+			return fmt.Errorf("name and password are required")
+		}
+		roles, err := u.pickedRoles(form)
+		if err != nil {
+			return err
+		}
+
+		content := fmt.Sprintf("Create user %s in %s without roles?", name, u.db)
+		if len(roles) > 0 {
+			content = fmt.Sprintf("Create user %s in %s with roles %s?", name, u.db, joinRoles(roles))
+		}
+		u.confirm("Create user", content, "Create", func(ctx context.Context) error {
+			return u.Dao.CreateUser(ctx, u.db, name, password, roles)
+		})
+		return nil
+	})
+}
+
+func (u *Users) showGrantRoleForm() {
+	user, ok := u.selectedUser()
+	if !ok {
+		return
+	}
+	u.formModal.Render("Grant roles to "+user.Name, "Grant", func(form *tview.Form) {
+		form.AddDropDown("Role", u.roleOptions(user.Roles), 0, nil)
+		form.AddInputField("Other roles", "", 40, nil, nil)
+	}, func(form *tview.Form) error {
+		roles, err := u.pickedRoles(form)
+		if err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return fmt.Errorf("at least one role is required")
+		}
+
+		content := fmt.Sprintf("Grant roles %s to user %s?", joinRoles(roles), user.Name)
+		u.confirm("Grant roles", content, "Grant", func(ctx context.Context) error {
+			return u.Dao.GrantRoles(ctx, u.db, user.Name, roles)
+		})
+		return nil
+	})
+}
+
+func (u *Users) showRevokeRoleForm() {
+	user, ok := u.selectedUser()
+	if !ok {
+		return
+	}
+	if len(user.Roles) == 0 {
+		modal.ShowInfo(u.App.Pages, fmt.Sprintf("User %s has no roles", user.Name))
+		return
+	}
+	options := []string{}
+	for _, role := range user.Roles {
+		options = append(options, role.String())
+	}
+	u.formModal.Render("Revoke role from "+user.Name, "Revoke", func(form *tview.Form) {
+		form.AddDropDown("Role", options, 0, nil)
+	}, func(form *tview.Form) error {
+		index, _ := form.GetFormItemByLabel("Role").(*tview.DropDown).GetCurrentOption()
+		role := user.Roles[index]
+
+		content := fmt.Sprintf("Revoke role %s from user %s?", role, user.Name)
+		u.confirm("Revoke role", content, "Revoke", func(ctx context.Context) error {
+			return u.Dao.RevokeRoles(ctx, u.db, user.Name, []mongo.RoleRef{role})
+		})
+		return nil
+	})
+}
+
+func (u *Users) showChangePasswordForm() {
+	user, ok := u.selectedUser()
+	if !ok {
+		return
+	}
+	u.formModal.Render("Change password of "+user.Name, "Change", func(form *tview.Form) {
+		form.AddPasswordField("Password", "", 40, '*', nil)
+		form.AddPasswordField("Repeat password", "", 40, '*', nil)
+	}, func(form *tview.Form) error {
+		password := form.GetFormItemByLabel("Password").(*tview.InputField).GetText()
+		if password == "" {
+			return fmt.Errorf("password cannot be empty")
+		}
+		if password != form.GetFormItemByLabel("Repeat password").(*tview.InputField).GetText() {
+			return fmt.Errorf("passwords do not match")
+		}
+
+		content := fmt.Sprintf("Change password of user %s?", user.Name)
+		u.confirm("Change password", content, "Change", func(ctx context.Context) error {
+			return u.Dao.ChangeUserPassword(ctx, u.db, user.Name, password)
+		})
+		return nil
+	})
+}
+
+func (u *Users) showDropUserModal() {
+	user, ok := u.selectedUser()
+	if !ok {
+		return
+	}
+	content := fmt.Sprintf("Drop user %s from %s?", user.Name, u.db)
+	u.confirm("Drop user", content, "Drop", func(ctx context.Context) error {
+		return u.Dao.DropUser(ctx, u.db, user.Name)
+	})
+}
+
+// pickedRoles returns role picked from the dropdown and roles typed
+// as comma separated role@db list, the list can be empty
+func (u *Users) pickedRoles(form *tview.Form) ([]mongo.RoleRef, error) {
+	roles := []mongo.RoleRef{}
+	if _, picked := form.GetFormItemByLabel("Role").(*tview.DropDown).GetCurrentOption(); picked != "" && picked != noRoleOption {
+		role, err := mongo.ParseRoleRef(picked, u.db)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	for _, text := range strings.Split(form.GetFormItemByLabel("Other roles").(*tview.InputField).GetText(), ",") {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		role, err := mongo.ParseRoleRef(text, u.db)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func joinRoles(roles []mongo.RoleRef) string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.String())
+	}
+	return strings.Join(names, ", ")
+}
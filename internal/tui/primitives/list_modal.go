@@ -66,6 +66,12 @@ func (lm *ListModal) GetCurrentItem() int {
 	return lm.list.GetCurrentItem()
 }
 
+// SetCurrentItem sets the currently selected item
+func (lm *ListModal) SetCurrentItem(index int) *ListModal {
+	lm.list.SetCurrentItem(index)
+	return lm
+}
+
 // Clear removes all items from the list
 func (lm *ListModal) Clear() *ListModal {
 	lm.list.Clear()
@@ -58,6 +58,10 @@ type ViewModal struct {
 
 	// The margin of the modal (only top and bottom)
 	marginTop, marginBottom int
+
+	// The current search and the background color of matched text
+	search      *util.TextSearch
+	searchColor tcell.Color
 }
 
 // NewViewModal returns a new modal message window.
@@ -250,15 +254,19 @@ func (m *ViewModal) Draw(screen tcell.Screen) {
 	}
 
 	numNextLinesToHighlight := m.calculateNextLinesToHighlight(lines)
+	absolutePosition := m.scrollPosition + m.selectedLine
 	for i := startLine; i < startLine+maxLines && i < totalHeight; i++ {
+		selected := i-startLine == m.selectedLine
+		highlighted := i > absolutePosition && i <= absolutePosition+numNextLinesToHighlight
+		if m.search != nil {
+			lines[i] = m.highlightMatches(lines[i], selected || highlighted)
+		}
 		lines[i] = m.formatAndColorizeLine(lines[i], i == startLine)
 
-		if i-startLine == m.selectedLine {
+		if selected {
 			lines[i] = m.highlightLine(lines[i], true)
-			absolutePosition := m.scrollPosition + m.selectedLine
-			for j := absolutePosition + 1; j <= absolutePosition+numNextLinesToHighlight; j++ {
-				lines[j] = m.highlightLine(lines[j], false)
-			}
+		} else if highlighted {
+			lines[i] = " " + m.highlightLine(lines[i], false)
 		} else {
 			lines[i] = " " + lines[i]
 		}
@@ -363,6 +371,16 @@ func (m *ViewModal) highlightLine(line string, withMark bool) string {
 	return fmt.Sprintf("[-:%s:b]%s[-:-:-]", m.highlightColor.CSS(), line)
 }
 
+// highlightMatches sets background of the text that matches the search,
+// background is restored to the highlight color for highlighted lines
+func (m *ViewModal) highlightMatches(line string, highlighted bool) string {
+	background := "-"
+	if highlighted {
+		background = m.highlightColor.CSS()
+	}
+	return m.search.Highlight(line, fmt.Sprintf("[:%s]", m.searchColor.CSS()), fmt.Sprintf("[:%s]", background))
+}
+
 func (m *ViewModal) MoveUp() {
 	if m.selectedLine > 0 {
 		m.selectedLine--
@@ -417,6 +435,52 @@ func (m *ViewModal) MoveToBottom() {
 	}
 }
 
+// SetSearch sets the search whose matches are highlighted with
+// given color, nil search disables highlighting
+func (m *ViewModal) SetSearch(search *util.TextSearch, color tcell.Color) *ViewModal {
+	m.search = search
+	m.searchColor = color
+	return m
+}
+
+// GetLines returns lines of the text wrapped in the same way as they are displayed
+func (m *ViewModal) GetLines() []string {
+	_, _, width, _ := m.GetRect()
+	if width <= 0 {
+		return strings.Split(m.text.Content, "\n")
+	}
+	return tview.WordWrap(m.text.Content, width)
+}
+
+// GetSelectedIndex returns the index of the selected line in the whole text
+func (m *ViewModal) GetSelectedIndex() int {
+	return m.scrollPosition + m.selectedLine
+}
+
+// SelectLine selects the line of given index, scrolling the text if the line is not visible
+func (m *ViewModal) SelectLine(index int) {
+	_, _, _, height := m.GetRect()
+	maxLines := height - m.marginBottom
+	totalLines := len(m.GetLines())
+	if index < 0 || index >= totalLines {
+		return
+	}
+	if maxLines <= 0 {
+		maxLines = totalLines
+	}
+
+	if index < m.scrollPosition || index >= m.scrollPosition+maxLines {
+		m.scrollPosition = index - maxLines/2
+	}
+	if m.scrollPosition > totalLines-maxLines {
+		m.scrollPosition = totalLines - maxLines
+	}
+	if m.scrollPosition < 0 {
+		m.scrollPosition = 0
+	}
+	m.selectedLine = index - m.scrollPosition
+}
+
 // TextAlignment sets the text alignment within the modal. This must be one of
 func (m *ViewModal) SetText(text Text) *ViewModal {
 	m.text = text
@@ -477,40 +541,50 @@ func (m *ViewModal) SetScrollable(scrollable bool) *ViewModal {
 // copyType can be "full" or "value". "full" will copy the entire highlighted lines,
 // while "value" will copy only the value of the highlighted line.
 func (m *ViewModal) CopySelectedLine(copyFunc func(text string) error, copyType string) error {
+	textToCopy, ok := m.GetSelectedText(copyType)
+	if !ok {
+		return nil
+	}
+	return copyFunc(textToCopy)
+}
+
+// GetSelectedText returns the text of the selected line, copyType works the same
+// as in CopySelectedLine. The second value is false if no line is selected.
+func (m *ViewModal) GetSelectedText(copyType string) (string, bool) {
 	_, _, width, _ := m.GetRect()
 	width = width - 4
 	lines := tview.WordWrap(m.text.Content, width)
 	selectedLineIndex := m.scrollPosition + m.selectedLine
 
-	if selectedLineIndex >= 0 && selectedLineIndex < len(lines) {
-		numNextLinesToHighlight := m.calculateNextLinesToHighlight(lines)
-		highlightedLines := lines[selectedLineIndex : selectedLineIndex+numNextLinesToHighlight+1]
-
-		var textToCopy string
-		switch copyType {
-		case "full":
-			textToCopy = strings.Join(highlightedLines, "\n")
-			textToCopy = util.CleanJsonWhitespaces(textToCopy)
-		case "value":
-			// Join all highlighted lines
-			fullText := strings.Join(highlightedLines, " ")
-			// Split by the first colon to separate key and value
-			parts := strings.SplitN(fullText, ":", 2)
-			if len(parts) > 1 {
-				// Trim spaces and remove trailing comma if exists
-				textToCopy = strings.TrimSpace(parts[1])
-				textToCopy = strings.TrimSuffix(textToCopy, ",")
-			} else {
-				textToCopy = strings.TrimSpace(fullText)
-			}
-			// Clean up JSON whitespaces
-			textToCopy = util.CleanJsonWhitespaces(textToCopy)
-		default:
-			textToCopy = strings.Join(highlightedLines, "\n")
-		}
+	if selectedLineIndex < 0 || selectedLineIndex >= len(lines) {
+		return "", false
+	}
 
-		textToCopy = strings.TrimSpace(textToCopy)
-		return copyFunc(textToCopy)
+	numNextLinesToHighlight := m.calculateNextLinesToHighlight(lines)
+	highlightedLines := lines[selectedLineIndex : selectedLineIndex+numNextLinesToHighlight+1]
+
+	var selectedText string
+	switch copyType {
+	case "full":
+		selectedText = strings.Join(highlightedLines, "\n")
+		selectedText = util.CleanJsonWhitespaces(selectedText)
+	case "value":
+		// Join all highlighted lines
+		fullText := strings.Join(highlightedLines, " ")
+		// Split by the first colon to separate key and value
+		parts := strings.SplitN(fullText, ":", 2)
+		if len(parts) > 1 {
+			// Trim spaces and remove trailing comma if exists
+			selectedText = strings.TrimSpace(parts[1])
+			selectedText = strings.TrimSuffix(selectedText, ",")
+		} else {
+			selectedText = strings.TrimSpace(fullText)
+		}
+		// Clean up JSON whitespaces
+		selectedText = util.CleanJsonWhitespaces(selectedText)
+	default:
+		selectedText = strings.Join(highlightedLines, "\n")
 	}
-	return nil
+
+	return strings.TrimSpace(selectedText), true
 }
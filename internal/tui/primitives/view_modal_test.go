@@ -1,9 +1,12 @@
 package primitives
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/kopecmaciej/vi-mongo/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,6 +156,18 @@ func TestHighlightLine(t *testing.T) {
 	}
 }
 
+func TestHighlightMatches(t *testing.T) {
+	m := NewViewModal()
+	m.SetHighlightColor(tcell.ColorYellow)
+	search, err := util.NewTextSearch("john", false)
+	assert.NoError(t, err)
+	m.SetSearch(search, tcell.ColorRed)
+
+	line := `  "name": "John",`
+	assert.Equal(t, `  "name": "[:#FF0000]John[:-]",`, m.highlightMatches(line, false))
+	assert.Equal(t, `  "name": "[:#FF0000]John[:#FFFF00]",`, m.highlightMatches(line, true))
+}
+
 func TestCopySelectedLine(t *testing.T) {
 	m := NewViewModal()
 	m.SetRect(0, 0, 50, 10) // Set a fixed size for testing
@@ -225,3 +240,29 @@ func TestCopySelectedLine(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectLine(t *testing.T) {
+	m := NewViewModal()
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(`  "key%d": "value",`, i)
+	}
+	m.SetText(Text{Content: strings.Join(lines, "\n")})
+	// 5 visible lines
+	m.SetRect(0, 0, 80, 5+m.marginBottom)
+
+	m.SelectLine(3)
+	assert.Equal(t, 0, m.scrollPosition)
+	assert.Equal(t, 3, m.GetSelectedIndex())
+
+	m.SelectLine(10)
+	assert.Equal(t, 8, m.scrollPosition)
+	assert.Equal(t, 10, m.GetSelectedIndex())
+
+	m.SelectLine(19)
+	assert.Equal(t, 15, m.scrollPosition)
+	assert.Equal(t, 19, m.GetSelectedIndex())
+
+	m.SelectLine(25)
+	assert.Equal(t, 19, m.GetSelectedIndex())
+}
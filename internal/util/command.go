@@ -0,0 +1,87 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	CommandUse    = "use"
+	CommandColl   = "coll"
+	CommandFind   = "find"
+	CommandSort   = "sort"
+	CommandLimit  = "limit"
+	CommandPage   = "page"
+	CommandExport = "export"
+	CommandDrop   = "drop"
+	CommandQuit   = "q"
+)
+
+// Commands are all commands available in the command line
+var Commands = []string{
+	CommandUse,
+	CommandColl,
+	CommandFind,
+	CommandSort,
+	CommandLimit,
+	CommandPage,
+	CommandExport,
+	CommandDrop,
+	CommandQuit,
+}
+
+// Command is a parsed line from the command line
+type Command struct {
+	Name string
+	Args string
+}
+
+// ParseCommand parses text like ":limit 50" into command name and its arguments
+func ParseCommand(line string) (Command, error) {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if line == "" {
+		return Command{}, fmt.Errorf("empty command")
+	}
+
+	name, args, _ := strings.Cut(line, " ")
+	for _, command := range Commands {
+		if command == name {
+			return Command{Name: name, Args: strings.TrimSpace(args)}, nil
+		}
+	}
+	return Command{}, fmt.Errorf("unknown command: %s", name)
+}
+
+// CompleteCommand returns all lines that complete the given one, command names
+// are completed first, then databases for "use" and collections for "coll"
+func CompleteCommand(line string, dbs []string, colls []string) []string {
+	line = strings.TrimLeft(line, " ")
+	name, arg, hasArg := strings.Cut(line, " ")
+
+	var prefix string
+	var candidates []string
+	switch {
+	case !hasArg:
+		candidates = Commands
+		arg = name
+	case name == CommandUse:
+		prefix = name + " "
+		candidates = dbs
+	case name == CommandColl:
+		prefix = name + " "
+		candidates = colls
+	default:
+		return nil
+	}
+
+	arg = strings.TrimLeft(arg, " ")
+	completions := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, arg) {
+			completions = append(completions, prefix+candidate)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    Command
+		wantErr bool
+	}{
+		{"with colon", ":limit 50", Command{Name: CommandLimit, Args: "50"}, false},
+		{"without colon", "use test", Command{Name: CommandUse, Args: "test"}, false},
+		{"without args", ":q", Command{Name: CommandQuit}, false},
+		{"json args", `:find { "name": "John" }`, Command{Name: CommandFind, Args: `{ "name": "John" }`}, false},
+		{"extra spaces", "  :page   3 ", Command{Name: CommandPage, Args: "3"}, false},
+		{"empty", ":", Command{}, true},
+		{"unknown", ":wq", Command{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommand(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	dbs := []string{"test", "admin", "testing"}
+	colls := []string{"users", "orders", "user_roles"}
+
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"command names", "l", []string{"limit"}},
+		{"all commands", "", []string{"coll", "drop", "export", "find", "limit", "page", "q", "sort", "use"}},
+		{"databases", "use te", []string{"use test", "use testing"}},
+		{"all databases", "use ", []string{"use admin", "use test", "use testing"}},
+		{"collections", "coll user", []string{"coll user_roles", "coll users"}},
+		{"no completion for args", "limit 5", nil},
+		{"no match", "use x", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompleteCommand(tt.line, dbs, colls))
+		})
+	}
+}
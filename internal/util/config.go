@@ -31,6 +31,10 @@ func mergeConfigsRecursive(loaded, defaultValue reflect.Value) {
 			if field.String() == "" {
 				field.Set(defaultField)
 			}
+		case reflect.Ptr:
+			if field.IsNil() {
+				field.Set(defaultField)
+			}
 		case reflect.Slice:
 			if field.Len() == 0 {
 				field.Set(defaultField)
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNestedConfig struct {
+	Name  string
+	Depth *int
+	Size  int
+}
+
+type testConfig struct {
+	Path   string
+	Items  []string
+	Nested testNestedConfig
+}
+
+func TestMergeConfigs(t *testing.T) {
+	defaultDepth, loadedDepth := 2, 0
+	defaultConfig := &testConfig{
+		Path:   "/tmp",
+		Items:  []string{"a"},
+		Nested: testNestedConfig{Name: "default", Depth: &defaultDepth, Size: 10},
+	}
+
+	t.Run("missing values are set to defaults", func(t *testing.T) {
+		loaded := &testConfig{}
+		MergeConfigs(loaded, defaultConfig)
+
+		assert.Equal(t, "/tmp", loaded.Path)
+		assert.Equal(t, []string{"a"}, loaded.Items)
+		assert.Equal(t, "default", loaded.Nested.Name)
+		assert.Equal(t, 2, *loaded.Nested.Depth)
+	})
+
+	t.Run("zero values of ints and pointers are kept", func(t *testing.T) {
+		loaded := &testConfig{Nested: testNestedConfig{Depth: &loadedDepth}}
+		MergeConfigs(loaded, defaultConfig)
+
+		assert.Equal(t, 0, *loaded.Nested.Depth)
+		assert.Equal(t, 0, loaded.Nested.Size)
+	})
+}
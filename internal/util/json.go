@@ -1,6 +1,10 @@
 package util
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"unicode"
 )
@@ -11,6 +15,32 @@ func IsJsonEmpty(s string) bool {
 	return s == "" || s == "{}"
 }
 
+// ValidateJson checks if data is a valid JSON, the returned
+// error contains line and column of the first syntax error
+func ValidateJson(data []byte) error {
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	// offset is the number of bytes read before the error occurred
+	offset := int(syntaxErr.Offset)
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(data[:offset], '\n') - 1
+	if column == 0 && line > 1 {
+		// error at the end of the line is reported at its last character
+		line--
+		column = offset - bytes.LastIndexByte(data[:offset-1], '\n') - 1
+	}
+	column = max(column, 1)
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
 // CleanJsonWhitespaces removes new lines and redundant spaces from a JSON string
 // and also removes comma from the end of the string
 func CleanJsonWhitespaces(s string) string {
@@ -70,3 +70,28 @@ func TestCleanJsonWhitespaces(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateJson(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Valid", "{\n  \"a\": 1\n}", ""},
+		{"Missing comma", "{\n  \"a\": 1\n  \"b\": 2\n}", "line 3, column 3: invalid character '\"' after object key:value pair"},
+		{"Trailing comma", "{\n  \"a\": 1,\n}", "line 3, column 1: invalid character '}' looking for beginning of object key string"},
+		{"Unexpected end", "{\n  \"a\": 1\n", "line 2, column 9: unexpected end of JSON input"},
+		{"Empty", "", "line 1, column 1: unexpected end of JSON input"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateJson([]byte(tc.input))
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -3,23 +3,37 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
-	TypeString   = "String"
-	TypeInt      = "Int"
-	TypeDouble   = "Double"
-	TypeBool     = "Bool"
-	TypeObjectId = "ObjectID"
-	TypeDate     = "Date"
-	TypeArray    = "Array"
-	TypeObject   = "Object"
-	TypeMixed    = "Mixed"
-	TypeNull     = "Null"
+	TypeString     = "String"
+	TypeInt        = "Int"
+	TypeLong       = "Long"
+	TypeDouble     = "Double"
+	TypeDecimal    = "Decimal128"
+	TypeBool       = "Bool"
+	TypeObjectId   = "ObjectID"
+	TypeDate       = "Date"
+	TypeTimestamp  = "Timestamp"
+	TypeArray      = "Array"
+	TypeObject     = "Object"
+	TypeBinary     = "BinData"
+	TypeRegex      = "Regex"
+	TypeJavaScript = "JavaScript"
+	TypeSymbol     = "Symbol"
+	TypeDBPointer  = "DBPointer"
+	TypeMinKey     = "MinKey"
+	TypeMaxKey     = "MaxKey"
+	TypeUndefined  = "Undefined"
+	TypeMixed      = "Mixed"
+	TypeNull       = "Null"
 )
 
 func GetSortedKeysWithTypes(documents []primitive.M, typeColor string) []string {
@@ -58,6 +72,10 @@ func GetValueByType(v interface{}) string {
 		return t.Hex()
 	case primitive.DateTime:
 		return t.Time().Format(time.RFC3339)
+	case primitive.Decimal128, primitive.Regex, primitive.Binary:
+		return fmt.Sprintf("%s", t)
+	case primitive.Timestamp:
+		return fmt.Sprintf("Timestamp(%d, %d)", t.T, t.I)
 	case primitive.A, primitive.D, primitive.M, map[string]interface{}, []interface{}:
 		b, _ := json.Marshal(t)
 		return string(b)
@@ -71,21 +89,233 @@ func GetMongoType(v interface{}) string {
 	switch v.(type) {
 	case string:
 		return TypeString
-	case int, int32, int64:
+	case int, int32:
 		return TypeInt
+	case int64:
+		return TypeLong
 	case float32, float64:
 		return TypeDouble
+	case primitive.Decimal128:
+		return TypeDecimal
 	case bool:
 		return TypeBool
 	case primitive.ObjectID:
 		return TypeObjectId
 	case primitive.DateTime:
 		return TypeDate
-	case primitive.A:
+	case primitive.Timestamp:
+		return TypeTimestamp
+	case primitive.A, []interface{}:
 		return TypeArray
-	case primitive.D, primitive.M:
+	case primitive.D, primitive.M, map[string]interface{}:
 		return TypeObject
+	case primitive.Binary:
+		return TypeBinary
+	case primitive.Regex:
+		return TypeRegex
+	case primitive.JavaScript, primitive.CodeWithScope:
+		return TypeJavaScript
+	case primitive.Symbol:
+		return TypeSymbol
+	case primitive.DBPointer:
+		return TypeDBPointer
+	case primitive.MinKey:
+		return TypeMinKey
+	case primitive.MaxKey:
+		return TypeMaxKey
+	case primitive.Undefined:
+		return TypeUndefined
 	default:
 		return TypeNull
 	}
 }
+
+// ParseValueByType parses the text to the value of given MongoDB type,
+// only scalar types are supported
+func ParseValueByType(text string, mongoType string) (interface{}, error) {
+	switch mongoType {
+	case TypeString:
+		return text, nil
+	case TypeInt:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		if value >= math.MinInt32 && value <= math.MaxInt32 {
+			return int32(value), nil
+		}
+		return value, nil
+	case TypeLong:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return value, nil
+	case TypeDouble:
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return value, nil
+	case TypeDecimal:
+		value, err := primitive.ParseDecimal128(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return value, nil
+	case TypeBool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return value, nil
+	case TypeObjectId:
+		value, err := primitive.ObjectIDFromHex(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return value, nil
+	case TypeDate:
+		value, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value, expected RFC3339 format: %s", mongoType, text)
+		}
+		return primitive.NewDateTimeFromTime(value), nil
+	case TypeNull:
+		if text != "null" {
+			return nil, fmt.Errorf("invalid %s value: %s", mongoType, text)
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("values of type %s cannot be parsed", mongoType)
+	}
+}
+
+// FlattenDocument returns the document with nested objects flattened into
+// dotted keys (address.city) up to maxDepth levels. Arrays are kept as they are,
+// unless their path is in expandedArrays, then elements are flattened
+// into indexed keys (tags.0, tags.1)
+func FlattenDocument(doc primitive.M, maxDepth int, expandedArrays map[string]bool) primitive.M {
+	flat := primitive.M{}
+	for key, value := range doc {
+		flattenValue(flat, key, value, 1, maxDepth, expandedArrays)
+	}
+	return flat
+}
+
+func flattenValue(flat primitive.M, path string, value interface{}, depth, maxDepth int, expandedArrays map[string]bool) {
+	switch v := value.(type) {
+	case primitive.M:
+		flattenObject(flat, path, v, depth, maxDepth, expandedArrays)
+	case map[string]interface{}:
+		flattenObject(flat, path, primitive.M(v), depth, maxDepth, expandedArrays)
+	case primitive.D:
+		flattenObject(flat, path, v.Map(), depth, maxDepth, expandedArrays)
+	case primitive.A:
+		if !expandedArrays[path] || len(v) == 0 {
+			flat[path] = value
+			return
+		}
+		for i, item := range v {
+			flattenValue(flat, fmt.Sprintf("%s.%d", path, i), item, depth, maxDepth, expandedArrays)
+		}
+	default:
+		flat[path] = value
+	}
+}
+
+func flattenObject(flat primitive.M, path string, obj primitive.M, depth, maxDepth int, expandedArrays map[string]bool) {
+	if depth > maxDepth || len(obj) == 0 {
+		flat[path] = obj
+		return
+	}
+	for key, value := range obj {
+		flattenValue(flat, path+"."+key, value, depth+1, maxDepth, expandedArrays)
+	}
+}
+
+// GetValueByPath returns the value under dotted path (address.city, tags.0)
+func GetValueByPath(doc primitive.M, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case primitive.M:
+			value, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case map[string]interface{}:
+			value, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case primitive.D:
+			value, ok := v.Map()[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case primitive.A:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// SetValueByPath returns copy of the document with the value set under dotted path,
+// missing objects on the path are created
+func SetValueByPath(doc primitive.M, path string, value interface{}) (primitive.M, error) {
+	updated, err := setValue(doc, strings.Split(path, "."), value)
+	if err != nil {
+		return nil, err
+	}
+	return updated.(primitive.M), nil
+}
+
+func setValue(current interface{}, parts []string, value interface{}) (interface{}, error) {
+	if len(parts) == 0 {
+		return value, nil
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return setValue(primitive.M{}, parts, value)
+	case primitive.D:
+		return setValue(v.Map(), parts, value)
+	case map[string]interface{}:
+		return setValue(primitive.M(v), parts, value)
+	case primitive.M:
+		copied := primitive.M{}
+		for key, val := range v {
+			copied[key] = val
+		}
+		updated, err := setValue(v[parts[0]], parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		copied[parts[0]] = updated
+		return copied, nil
+	case primitive.A:
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || index < 0 || index >= len(v) {
+			return nil, fmt.Errorf("invalid array index: %s", parts[0])
+		}
+		copied := append(primitive.A{}, v...)
+		updated, err := setValue(v[index], parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		copied[index] = updated
+		return copied, nil
+	default:
+		return nil, fmt.Errorf("cannot set field %s on value of type %s", parts[0], GetMongoType(current))
+	}
+}
@@ -41,6 +41,8 @@ func TestGetValueByType(t *testing.T) {
 		{"DateTime", primitive.NewDateTimeFromTime(time.Now()), ""}, // Formatted time will be different
 		{"Array", primitive.A{"a", "b"}, `["a","b"]`},
 		{"Object", primitive.M{"key": "value"}, `{"key":"value"}`},
+		{"Decimal128", func() primitive.Decimal128 { d, _ := primitive.ParseDecimal128("1.5"); return d }(), "1.5"},
+		{"Timestamp", primitive.Timestamp{T: 10, I: 2}, "Timestamp(10, 2)"},
 		{"Null", nil, "null"},
 	}
 
@@ -67,13 +69,26 @@ func TestGetMongoType(t *testing.T) {
 	}{
 		{"String", "test", TypeString},
 		{"Int", 42, TypeInt},
+		{"Int32", int32(42), TypeInt},
+		{"Long", int64(42), TypeLong},
 		{"Float", 3.14, TypeDouble},
+		{"Decimal128", primitive.NewDecimal128(0, 42), TypeDecimal},
 		{"Bool", true, TypeBool},
 		{"ObjectID", primitive.NewObjectID(), TypeObjectId},
 		{"DateTime", primitive.NewDateTimeFromTime(time.Now()), TypeDate},
 		{"Array", primitive.A{"a", "b"}, TypeArray},
 		{"Object", primitive.M{"key": "value"}, TypeObject},
+		{"Ordered object", primitive.D{{Key: "key", Value: "value"}}, TypeObject},
+		{"Timestamp", primitive.Timestamp{T: 1, I: 1}, TypeTimestamp},
+		{"Binary", primitive.Binary{Data: []byte("a")}, TypeBinary},
+		{"Regex", primitive.Regex{Pattern: "a"}, TypeRegex},
+		{"JavaScript", primitive.JavaScript("function() {}"), TypeJavaScript},
+		{"Symbol", primitive.Symbol("a"), TypeSymbol},
+		{"MinKey", primitive.MinKey{}, TypeMinKey},
+		{"MaxKey", primitive.MaxKey{}, TypeMaxKey},
+		{"Undefined", primitive.Undefined{}, TypeUndefined},
 		{"Null", nil, TypeNull},
+		{"Primitive null", primitive.Null{}, TypeNull},
 	}
 
 	for _, tc := range testCases {
@@ -83,3 +98,134 @@ func TestGetMongoType(t *testing.T) {
 		})
 	}
 }
+
+func TestParseValueByType(t *testing.T) {
+	objectId := primitive.NewObjectID()
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	decimal, _ := primitive.ParseDecimal128("1.5")
+
+	testCases := []struct {
+		name      string
+		text      string
+		mongoType string
+		expected  interface{}
+		wantErr   bool
+	}{
+		{"String", "test", TypeString, "test", false},
+		{"Int", "42", TypeInt, int32(42), false},
+		{"Long", "3000000000", TypeInt, int64(3000000000), false},
+		{"Long type", "42", TypeLong, int64(42), false},
+		{"Decimal128", "1.5", TypeDecimal, decimal, false},
+		{"Invalid decimal", "abc", TypeDecimal, nil, true},
+		{"Invalid int", "4.2", TypeInt, nil, true},
+		{"Double", "3.14", TypeDouble, 3.14, false},
+		{"Bool", "true", TypeBool, true, false},
+		{"Invalid bool", "yes", TypeBool, nil, true},
+		{"ObjectID", objectId.Hex(), TypeObjectId, objectId, false},
+		{"Date", "2024-05-01T12:00:00Z", TypeDate, primitive.NewDateTimeFromTime(date), false},
+		{"Invalid date", "01/05/2024", TypeDate, nil, true},
+		{"Null", "null", TypeNull, nil, false},
+		{"Array", "[1]", TypeArray, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseValueByType(tc.text, tc.mongoType)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFlattenDocument(t *testing.T) {
+	doc := primitive.M{
+		"name": "John",
+		"address": primitive.M{
+			"city": "Berlin",
+			"geo":  primitive.M{"lat": 52.5, "lng": 13.4},
+		},
+		"tags":  primitive.A{"a", primitive.M{"b": 1}},
+		"empty": primitive.M{},
+	}
+
+	t.Run("Depth 1", func(t *testing.T) {
+		result := FlattenDocument(doc, 1, nil)
+		assert.Equal(t, primitive.M{
+			"name":         "John",
+			"address.city": "Berlin",
+			"address.geo":  primitive.M{"lat": 52.5, "lng": 13.4},
+			"tags":         primitive.A{"a", primitive.M{"b": 1}},
+			"empty":        primitive.M{},
+		}, result)
+	})
+
+	t.Run("Depth 2 with expanded array", func(t *testing.T) {
+		result := FlattenDocument(doc, 2, map[string]bool{"tags": true})
+		assert.Equal(t, primitive.M{
+			"name":            "John",
+			"address.city":    "Berlin",
+			"address.geo.lat": 52.5,
+			"address.geo.lng": 13.4,
+			"tags.0":          "a",
+			"tags.1.b":        1,
+			"empty":           primitive.M{},
+		}, result)
+	})
+
+	t.Run("Depth 0 keeps document", func(t *testing.T) {
+		result := FlattenDocument(doc, 0, nil)
+		assert.Equal(t, doc, result)
+	})
+}
+
+func TestGetValueByPath(t *testing.T) {
+	doc := primitive.M{
+		"address": primitive.M{"city": "Berlin"},
+		"tags":    primitive.A{"a", primitive.D{{Key: "b", Value: 1}}},
+	}
+
+	value, ok := GetValueByPath(doc, "address.city")
+	assert.True(t, ok)
+	assert.Equal(t, "Berlin", value)
+
+	value, ok = GetValueByPath(doc, "tags.1.b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	_, ok = GetValueByPath(doc, "tags.5")
+	assert.False(t, ok)
+
+	_, ok = GetValueByPath(doc, "address.zip")
+	assert.False(t, ok)
+}
+
+func TestSetValueByPath(t *testing.T) {
+	doc := primitive.M{
+		"address": primitive.M{"city": "Berlin"},
+		"tags":    primitive.A{"a", "b"},
+	}
+
+	result, err := SetValueByPath(doc, "address.city", "Paris")
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris", result["address"].(primitive.M)["city"])
+	assert.Equal(t, "Berlin", doc["address"].(primitive.M)["city"], "original document should not be modified")
+
+	result, err = SetValueByPath(doc, "tags.1", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.A{"a", "c"}, result["tags"])
+	assert.Equal(t, primitive.A{"a", "b"}, doc["tags"])
+
+	result, err = SetValueByPath(doc, "meta.created", true)
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.M{"created": true}, result["meta"])
+
+	_, err = SetValueByPath(doc, "tags.5", "x")
+	assert.Error(t, err)
+
+	_, err = SetValueByPath(doc, "address.city.name", "x")
+	assert.Error(t, err)
+}
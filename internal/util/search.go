@@ -0,0 +1,92 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TextSearch matches text against a plain or regex pattern. Like in vim
+// "smartcase" mode, the search is case insensitive unless the pattern
+// contains upper case letters
+type TextSearch struct {
+	Pattern string
+	Regex   bool
+
+	re *regexp.Regexp
+}
+
+// NewTextSearch compiles the pattern, plain patterns are matched literally
+func NewTextSearch(pattern string, regex bool) (*TextSearch, error) {
+	expr := pattern
+	if !regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if !strings.ContainsFunc(pattern, unicode.IsUpper) {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &TextSearch{Pattern: pattern, Regex: regex, re: re}, nil
+}
+
+// Matches checks if the text contains the pattern
+func (s *TextSearch) Matches(text string) bool {
+	return s.re.MatchString(text)
+}
+
+// Highlight wraps every match in the text with startTag and endTag,
+// like "[:#7C5E10]" and "[:-]" tview color tags
+func (s *TextSearch) Highlight(text, startTag, endTag string) string {
+	var highlighted strings.Builder
+	last := 0
+	for _, match := range s.re.FindAllStringIndex(text, -1) {
+		// regex can match empty strings, there is nothing to highlight then
+		if match[0] == match[1] {
+			continue
+		}
+		highlighted.WriteString(text[last:match[0]])
+		highlighted.WriteString(startTag)
+		highlighted.WriteString(text[match[0]:match[1]])
+		highlighted.WriteString(endTag)
+		last = match[1]
+	}
+	highlighted.WriteString(text[last:])
+	return highlighted.String()
+}
+
+// FindMatches returns indexes of all lines that contain the pattern
+func (s *TextSearch) FindMatches(lines []string) []int {
+	matches := []int{}
+	for i, line := range lines {
+		if s.Matches(line) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// NextMatch returns the position in matches of the first match after
+// (or before if backward) the current index, wrapping around the ends.
+// It returns -1 if there are no matches
+func NextMatch(matches []int, current int, backward bool) int {
+	if len(matches) == 0 {
+		return -1
+	}
+	if backward {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < current {
+				return i
+			}
+		}
+		return len(matches) - 1
+	}
+	for i, match := range matches {
+		if match > current {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextSearchMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		regex   bool
+		text    string
+		want    bool
+	}{
+		{"plain lower case ignores case", "john", false, `"name": "John"`, true},
+		{"plain upper case is case sensitive", "John", false, `"name": "john"`, false},
+		{"plain pattern is literal", "a.c", false, "abc", false},
+		{"plain pattern with special chars", "a.c", false, "a.c", true},
+		{"regex", `^\d+$`, true, "12345", true},
+		{"regex no match", `^\d+$`, true, "123a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search, err := NewTextSearch(tt.pattern, tt.regex)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, search.Matches(tt.text))
+		})
+	}
+}
+
+func TestNewTextSearchInvalidRegex(t *testing.T) {
+	_, err := NewTextSearch("a(b", true)
+	assert.Error(t, err)
+
+	_, err = NewTextSearch("a(b", false)
+	assert.NoError(t, err)
+}
+
+func TestTextSearchFindMatches(t *testing.T) {
+	search, err := NewTextSearch("foo", false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{0, 2}, search.FindMatches([]string{"foo", "bar", "FOObar"}))
+	assert.Equal(t, []int{}, search.FindMatches([]string{"bar"}))
+}
+
+func TestTextSearchHighlight(t *testing.T) {
+	search, err := NewTextSearch("foo", false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "<foo>bar<FOO>", search.Highlight("foobarFOO", "<", ">"))
+	assert.Equal(t, "bar", search.Highlight("bar", "<", ">"))
+
+	search, err = NewTextSearch(`\d*`, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "a<12>b<3>", search.Highlight("a12b3", "<", ">"))
+}
+
+func TestNextMatch(t *testing.T) {
+	matches := []int{2, 5, 9}
+
+	tests := []struct {
+		name     string
+		current  int
+		backward bool
+		want     int
+	}{
+		{"forward from start", 0, false, 0},
+		{"forward skips current", 2, false, 1},
+		{"forward wraps", 9, false, 0},
+		{"backward", 6, true, 1},
+		{"backward skips current", 5, true, 0},
+		{"backward wraps", 2, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextMatch(matches, tt.current, tt.backward))
+		})
+	}
+
+	assert.Equal(t, -1, NextMatch(nil, 0, false))
+}
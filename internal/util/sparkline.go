@@ -0,0 +1,35 @@
+package util
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the last width values as a line of block characters
+// scaled between 0 and the maximum value, shorter history is padded
+// with spaces on the left
+func Sparkline(values []float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	line := make([]rune, 0, width)
+	for i := len(values); i < width; i++ {
+		line = append(line, ' ')
+	}
+	for _, v := range values {
+		index := 0
+		if max > 0 && v > 0 {
+			index = int(v / max * float64(len(sparkBlocks)-1))
+		}
+		line = append(line, sparkBlocks[index])
+	}
+	return string(line)
+}
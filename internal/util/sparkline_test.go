@@ -0,0 +1,27 @@
+package util
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"empty", nil, 3, "   "},
+		{"zeros", []float64{0, 0}, 2, "▁▁"},
+		{"scaled to maximum", []float64{0, 7, 14}, 3, "▁▄█"},
+		{"padded", []float64{1}, 3, "  █"},
+		{"last values", []float64{7, 0, 7}, 2, "▁█"},
+		{"no width", []float64{1}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}